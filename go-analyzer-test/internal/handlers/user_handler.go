@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go-analyzer-test/internal/interfaces"
+)
+
+// UserHandler serves users stored in a Repository.
+type UserHandler struct {
+	repo interfaces.Repository
+}
+
+func NewUserHandler(repo interfaces.Repository) *UserHandler {
+	return &UserHandler{repo: repo}
+}
+
+func (h *UserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *UserHandler) create(w http.ResponseWriter, r *http.Request) {
+	var user map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := h.repo.Create(user); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
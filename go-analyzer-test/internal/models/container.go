@@ -0,0 +1,49 @@
+package models
+
+// Container holds items of one type.
+type Container[T any] interface {
+	Put(item T)
+	Items() []T
+}
+
+// LIFO is implemented by Stack for any element type.
+type LIFO[T any] interface {
+	Push(item T)
+	Pop() (T, bool)
+}
+
+// Set is a container of distinct keys.
+type Set[K comparable] interface {
+	Add(key K)
+	Has(key K) bool
+}
+
+// IntBag is a Container[int].
+type IntBag struct {
+	items []int
+}
+
+func (b *IntBag) Put(item int) {
+	b.items = append(b.items, item)
+}
+
+func (b *IntBag) Items() []int {
+	return b.items
+}
+
+// KeySet is a Set of any comparable key.
+type KeySet[K comparable] struct {
+	keys map[K]struct{}
+}
+
+func (s *KeySet[K]) Add(key K) {
+	if s.keys == nil {
+		s.keys = make(map[K]struct{})
+	}
+	s.keys[key] = struct{}{}
+}
+
+func (s *KeySet[K]) Has(key K) bool {
+	_, ok := s.keys[key]
+	return ok
+}
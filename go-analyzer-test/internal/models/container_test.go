@@ -0,0 +1,16 @@
+package models_test
+
+import "go-analyzer-test/internal/models"
+
+// pageContainer is a models.Container of pages.
+type pageContainer struct {
+	pages []*models.Page
+}
+
+func (c *pageContainer) Put(page *models.Page) {
+	c.pages = append(c.pages, page)
+}
+
+func (c *pageContainer) Items() []*models.Page {
+	return c.pages
+}
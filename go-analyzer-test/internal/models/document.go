@@ -0,0 +1,21 @@
+package models
+
+// Timestamps is embedded by value: its methods are always safe to call.
+type Timestamps struct {
+	Created int64 `json:"created" db:"created_at"`           // Unix seconds
+	Updated int64 `json:"updated,omitempty" db:"updated_at"` // Unix seconds, 0 if never
+}
+
+// Age returns the seconds elapsed since t was created.
+func (t Timestamps) Age(now int64) int64 {
+	return now - t.Created
+}
+
+// Document embeds Timestamps by value and Node by pointer, so Next and
+// SetNext panic on a Document whose Node is nil.
+type Document struct {
+	// Timestamps records when the document changed.
+	Timestamps
+	*Node
+	Title string
+}
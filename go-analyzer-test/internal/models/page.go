@@ -0,0 +1,22 @@
+package models
+
+// Page, Layout and Theme embed each other in a cycle, so that each reaches
+// the fields and methods of the others.
+type Page struct {
+	*Layout
+	Title string
+}
+
+type Layout struct {
+	*Theme
+	Columns int
+}
+
+type Theme struct {
+	*Page
+	Name string
+}
+
+func (p *Page) Render() string {
+	return p.Title
+}
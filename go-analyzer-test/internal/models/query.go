@@ -0,0 +1,34 @@
+package models
+
+import "strings"
+
+// Query is built up with chainable calls:
+//
+//	q := NewQuery("users").Where("active").Limit(10)
+type Query struct {
+	table      string
+	conditions []string
+	limit      int
+}
+
+func NewQuery(table string) *Query {
+	return &Query{table: table}
+}
+
+func (q *Query) Where(condition string) *Query {
+	q.conditions = append(q.conditions, condition)
+	return q
+}
+
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+func (q *Query) String() string {
+	s := "SELECT * FROM " + q.table
+	if len(q.conditions) > 0 {
+		s += " WHERE " + strings.Join(q.conditions, " AND ")
+	}
+	return s
+}
@@ -0,0 +1,29 @@
+package models
+
+// Stack is a generic last-in, first-out collection.
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(item T) {
+	s.items = append(s.items, item)
+}
+
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	item := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return item, true
+}
+
+// Map applies f to every element of items.
+func Map[T, U any](items []T, f func(T) U) []U {
+	mapped := make([]U, 0, len(items))
+	for _, item := range items {
+		mapped = append(mapped, f(item))
+	}
+	return mapped
+}
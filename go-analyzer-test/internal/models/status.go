@@ -0,0 +1,20 @@
+package models
+
+// Status is the lifecycle state of a record.
+type Status int
+
+const (
+	StatusDraft Status = iota
+	StatusActive
+	StatusArchived
+)
+
+// Priority levels leave gaps for future values.
+type Priority uint8
+
+const (
+	PriorityLow Priority = (iota + 1) * 10
+	PriorityMedium
+	_
+	PriorityHigh
+)
@@ -0,0 +1,17 @@
+package models
+
+type Celsius float64
+
+type Fahrenheit float64
+
+func (f Fahrenheit) Celsius() Celsius {
+	return Celsius((f - 32) * 5 / 9)
+}
+
+func (c Celsius) Fahrenheit() Fahrenheit {
+	return Fahrenheit(c*9/5 + 32)
+}
+
+func (c Celsius) Float() float64 {
+	return float64(c)
+}
@@ -0,0 +1,72 @@
+package models
+
+// Node is a singly linked list node referring to itself.
+type Node struct {
+	Value interface{}
+	next  *Node
+}
+
+func (n *Node) Next() *Node {
+	return n.next
+}
+
+func (n *Node) SetNext(next *Node) {
+	n.next = next
+}
+
+// Tree refers to itself through a slice of children.
+type Tree struct {
+	Value    interface{}
+	Children []*Tree
+	Parent   *Tree
+}
+
+func (t *Tree) Walk(visit func(*Tree) bool) {
+	if !visit(t) {
+		return
+	}
+	for _, child := range t.Children {
+		child.Walk(visit)
+	}
+}
+
+// Graph and vertex refer to each other.
+type Graph struct {
+	Vertices map[string]*vertex
+}
+
+type vertex struct {
+	Graph *Graph
+	Edges []*vertex
+}
+
+func (g *Graph) Vertex(name string) *vertex {
+	return g.Vertices[name]
+}
+
+func (g *Graph) Each(visit func(struct{ V *vertex })) {
+	for _, v := range g.Vertices {
+		visit(struct{ V *vertex }{v})
+	}
+}
+
+// Depth measures the tree with a local stack frame type.
+func (t *Tree) Depth() int {
+	type frame struct {
+		node  *Tree
+		depth int
+	}
+	deepest := 0
+	stack := []frame{{t, 1}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if top.depth > deepest {
+			deepest = top.depth
+		}
+		for _, child := range top.node.Children {
+			stack = append(stack, frame{child, top.depth + 1})
+		}
+	}
+	return deepest
+}
@@ -0,0 +1,37 @@
+// Package naming holds identifiers whose exported-ness is easy to get wrong
+// with byte-based checks: leading acronyms and non-ASCII letters.
+package naming
+
+// Repository is implemented by IDRepository.
+type Repository interface {
+	FindByID(id string) (string, error)
+}
+
+// HTTPServer starts with an acronym and is exported.
+type HTTPServer struct {
+	Addr string
+}
+
+func (s *HTTPServer) ServeHTTP() error { return nil }
+
+// IDRepository starts with a two-letter acronym and is exported.
+type IDRepository struct {
+	byID map[string]string
+}
+
+func (r *IDRepository) FindByID(id string) (string, error) { return r.byID[id], nil }
+
+// Ärger starts with a non-ASCII upper-case letter and is exported.
+type Ärger struct{}
+
+func (Ärger) Größe() int { return 0 }
+
+// ñandú starts with a non-ASCII lower-case letter and is unexported.
+type ñandú struct{}
+
+func (ñandú) FindByID(id string) (string, error) { return id, nil }
+
+// Δelta starts with an upper-case Greek letter and is exported.
+type Δelta interface {
+	Apply() error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// NotFoundError carries the missing ID.
+type NotFoundError struct {
+	ID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.ID)
+}
+
+// Cache returns errors of several kinds.
+type Cache struct {
+	items map[string]string
+}
+
+func (c *Cache) Get(id string) (string, error) {
+	if c.items == nil {
+		return "", errors.New("cache not initialized")
+	}
+	if id == "" {
+		return "", fmt.Errorf("empty id: %w", ErrNotFound)
+	}
+	item, ok := c.items[id]
+	if !ok {
+		return "", &NotFoundError{ID: id}
+	}
+	return item, nil
+}
+
+func (c *Cache) Must(id string) error {
+	if _, ok := c.items[id]; !ok {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package services
+
+import "go-analyzer-test/internal/interfaces"
+
+// CountingRepository decorates a repository, counting deletions.
+type CountingRepository struct {
+	interfaces.Repository
+	deletes int
+}
+
+func (r *CountingRepository) Delete(id string) error {
+	r.deletes++
+	return r.Repository.Delete(id)
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"strconv"
+
+	"go-analyzer-test/internal/models"
+)
+
+// History keeps undo stacks of instantiated generic types.
+type History struct {
+	messages models.Stack[string]
+	ids      models.Stack[int]
+}
+
+func (h *History) Record(id int, msg string) {
+	h.ids.Push(id)
+	h.messages.Push(msg)
+}
+
+func (h *History) Labels(ids []int) []string {
+	return models.Map(ids, strconv.Itoa)
+}
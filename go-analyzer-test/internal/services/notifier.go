@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type Notifier struct {
+	sent []string
+}
+
+// Notify propagates its context.
+func (n *Notifier) Notify(ctx context.Context, msg string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+// Broadcast accepts a context but never checks or passes it on.
+func (n *Notifier) Broadcast(ctx context.Context, msgs []string) error {
+	for _, msg := range msgs {
+		n.sent = append(n.sent, fmt.Sprintf("broadcast: %s", msg))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package services
+
+import "go-analyzer-test/internal/interfaces"
+
+// UserService stores its repository as a dependency.
+type UserService struct {
+	repo interfaces.Repository
+}
+
+func NewUserService(repo interfaces.Repository) *UserService {
+	return &UserService{repo: repo}
+}
+
+// Use swaps the repository, returning the previous one.
+func (s *UserService) Use(repo interfaces.Repository) interfaces.Repository {
+	previous := s.repo
+	s.repo = repo
+	return previous
+}
@@ -0,0 +1,32 @@
+package services
+
+// fakeRepository is an in-memory interfaces.Repository for tests.
+type fakeRepository struct {
+	entities map[string]interface{}
+}
+
+func (r *fakeRepository) Create(entity interface{}) error {
+	return nil
+}
+
+func (r *fakeRepository) Update(id string, entity interface{}) error {
+	r.entities[id] = entity
+	return nil
+}
+
+func (r *fakeRepository) Delete(id string) error {
+	delete(r.entities, id)
+	return nil
+}
+
+func (r *fakeRepository) FindById(id string) (interface{}, error) {
+	return r.entities[id], nil
+}
+
+func (r *fakeRepository) FindAll() ([]interface{}, error) {
+	all := make([]interface{}, 0, len(r.entities))
+	for _, entity := range r.entities {
+		all = append(all, entity)
+	}
+	return all, nil
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// accessorKind classifies method by naming convention as "getter" or
+// "setter" of a field of its receiver's struct, or returns "".
+//
+// A getter takes no parameters and returns one value of the field's type,
+// and is named after the field, as Name or GetName for a field name or
+// Name. A setter is named SetName, takes one parameter of the field's type
+// and returns nothing. The method body is not looked at.
+func accessorKind(method *types.Func) string {
+	named := receiverType(method)
+	if named == nil {
+		return ""
+	}
+	strct, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return ""
+	}
+	signature := method.Type().(*types.Signature)
+	params, results := signature.Params(), signature.Results()
+
+	switch {
+	case params.Len() == 0 && results.Len() == 1:
+		names := []string{method.Name()}
+		if name, ok := strings.CutPrefix(method.Name(), "Get"); ok && name != "" {
+			names = append(names, name)
+		}
+		for _, name := range names {
+			if field := accessedField(strct, name); field != nil && types.Identical(field.Type(), results.At(0).Type()) {
+				return "getter"
+			}
+		}
+	case params.Len() == 1 && results.Len() == 0 && !signature.Variadic():
+		name, ok := strings.CutPrefix(method.Name(), "Set")
+		if !ok || name == "" {
+			return ""
+		}
+		if field := accessedField(strct, name); field != nil && types.Identical(field.Type(), params.At(0).Type()) {
+			return "setter"
+		}
+	}
+	return ""
+}
+
+// accessedField returns the field of strct an accessor named after name
+// refers to: name itself, or name with its first letter lowercased.
+func accessedField(strct *types.Struct, name string) *types.Var {
+	r, size := utf8.DecodeRuneInString(name)
+	unexported := string(unicode.ToLower(r)) + name[size:]
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		if field.Name() == name || field.Name() == unexported {
+			return field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1238 @@
+// Package analyzer reports the interfaces and structs of a Go module, how
+// they relate, and the findings of the optional analyses built on them. The
+// goanalyzer command is a thin wrapper around it:
+//
+//	result, err := analyzer.New().Analyze(ctx, "./path/to/module")
+//
+// Results do not depend on the order packages are loaded or analyzed in,
+// so reports of the same code can be compared as golden files:
+// declarations are listed by package path, then name, then position,
+// findings by package path, then position, and methods and implemented
+// interfaces by name. Fields, parameters, embedded types and cycle paths
+// keep declaration order.
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Position locates a declaration or expression. Path is relative to the
+// root of the module holding the file, with forward slashes, so that it is
+// unique within the module. Line and Column are those
+// of its start, or of the name of a declaration; EndLine and EndColumn,
+// just past its end, are set where the extent is known: for declarations
+// of the analyzed packages, from their name to the end of their body, and
+// for expressions and statements.
+type Position struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	EndColumn int    `json:"endColumn,omitempty"`
+}
+
+// newPosition returns the position of pos, extending to end unless that is
+// token.NoPos.
+func newPosition(fset *token.FileSet, pos, end token.Pos) Position {
+	start := fset.Position(pos)
+	position := Position{
+		Path:   makeRelativePath(start.Filename),
+		Line:   start.Line,
+		Column: start.Column,
+	}
+	if end.IsValid() {
+		finish := fset.Position(end)
+		position.EndLine, position.EndColumn = finish.Line, finish.Column
+	}
+	return position
+}
+
+// line returns p without its columns and end, as the passes over function
+// bodies and declaration sources key their results.
+func (p Position) line() Position {
+	return Position{Path: p.Path, Line: p.Line}
+}
+
+type Declaration struct {
+	Name     string   `json:"name"`
+	Package  string   `json:"package,omitempty"`
+	Position Position `json:"position"`
+	// TypeArguments instantiate a generic interface a struct implements,
+	// and ReceiverKind is "value", "pointer" or "both" as the struct's
+	// methods implementing it have value or pointer receivers. Unless it
+	// is "value", only a pointer to the struct implements the interface.
+	TypeArguments []string `json:"typeArguments,omitempty"`
+	ReceiverKind  string   `json:"receiverKind,omitempty"`
+}
+
+type ParamInfo struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TypeRef *TypeRef `json:"typeRef,omitempty"`
+}
+
+type FieldInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Signature string `json:"signature,omitempty"`
+	Exported  bool   `json:"exported,omitempty"`
+	Embedded  bool   `json:"embedded,omitempty"`
+	// Tag is the struct tag as written, and Tags its values by key, such
+	// as "json" and "db".
+	Tag      string            `json:"tag,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Position Position          `json:"position"`
+	Doc      string            `json:"doc,omitempty"`
+	// ReadBy and WrittenBy name the methods of the struct accessing the
+	// field, with -field-access.
+	ReadBy    []string `json:"readBy,omitempty"`
+	WrittenBy []string `json:"writtenBy,omitempty"`
+}
+
+type MethodInfo struct {
+	ID                 string        `json:"id"`
+	Name               string        `json:"name"`
+	Position           Position      `json:"position"`
+	Receiver           *Declaration  `json:"receiver,omitempty"`
+	Doc                string        `json:"doc,omitempty"`
+	Parameters         []ParamInfo   `json:"parameters"`
+	ReturnTypes        []string      `json:"returnTypes"`
+	ReturnTypeRefs     []*TypeRef    `json:"returnTypeRefs,omitempty"`
+	Signature          string        `json:"signature,omitempty"`
+	ParamCount         int           `json:"paramCount"`
+	Variadic           bool          `json:"variadic,omitempty"`
+	ReturnCount        int           `json:"returnCount"`
+	Accessor           string        `json:"accessor,omitempty"`
+	GoroutineLaunches  []Position    `json:"goroutineLaunches,omitempty"`
+	Panics             []Position    `json:"panics,omitempty"`
+	Repanics           []Position    `json:"repanics,omitempty"`
+	TokenCount         int           `json:"tokenCount,omitempty"`
+	ReturnedErrorTypes []string      `json:"returnedErrorTypes,omitempty"`
+	Source             string        `json:"source,omitempty"`
+	Deprecated         bool          `json:"deprecated,omitempty"`
+	DeprecationNote    string        `json:"deprecationNote,omitempty"`
+	BuildConstraints   []string      `json:"buildConstraints,omitempty"`
+	ImplementedFrom    []Declaration `json:"implementedFrom"`
+	Promoted           bool          `json:"promoted,omitempty"`
+	// PromotedThroughPointer is set for methods promoted through a type
+	// embedded as a pointer, which panic when that field is nil.
+	PromotedThroughPointer bool         `json:"promotedThroughPointer,omitempty"`
+	Overrides              *Declaration `json:"overrides,omitempty"`
+}
+
+type InterfaceInfo struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	Package          string       `json:"package"`
+	Position         Position     `json:"position"`
+	Doc              string       `json:"doc,omitempty"`
+	TypeParams       []TypeParam  `json:"typeParams,omitempty"`
+	Methods          []MethodInfo `json:"methods"`
+	UsageKinds       []string     `json:"usageKinds,omitempty"`
+	Source           string       `json:"source,omitempty"`
+	Deprecated       bool         `json:"deprecated,omitempty"`
+	DeprecationNote  string       `json:"deprecationNote,omitempty"`
+	BuildConstraints []string     `json:"buildConstraints,omitempty"`
+	// InTest is set for interfaces declared in _test.go files.
+	InTest bool `json:"inTest,omitempty"`
+}
+
+type StructInfo struct {
+	ID                    string           `json:"id"`
+	Name                  string           `json:"name"`
+	Package               string           `json:"package"`
+	Position              Position         `json:"position"`
+	Doc                   string           `json:"doc,omitempty"`
+	TypeParams            []TypeParam      `json:"typeParams,omitempty"`
+	Methods               []MethodInfo     `json:"methods"`
+	EmbeddedTypes         []string         `json:"embeddedTypes"`
+	Embedded              []EmbeddedType   `json:"embedded,omitempty"`
+	IsEmpty               bool             `json:"isEmpty,omitempty"`
+	FieldCount            int              `json:"fieldCount,omitempty"`
+	EmbeddingDepth        int              `json:"embeddingDepth,omitempty"`
+	FunctionFields        []FieldInfo      `json:"functionFields,omitempty"`
+	Fields                []FieldInfo      `json:"fields,omitempty"`
+	ImplementedInterfaces []Declaration    `json:"implementedInterfaces"`
+	InterfaceOrder        []InterfaceOrder `json:"interfaceOrder,omitempty"`
+	MethodDependencies    []string         `json:"methodDependencies,omitempty"`
+	Source                string           `json:"source,omitempty"`
+	Deprecated            bool             `json:"deprecated,omitempty"`
+	DeprecationNote       string           `json:"deprecationNote,omitempty"`
+	BuildConstraints      []string         `json:"buildConstraints,omitempty"`
+	// InTest is set for structs declared in _test.go files.
+	InTest bool `json:"inTest,omitempty"`
+}
+
+// SchemaVersion identifies the shape of AnalysisResult. Bump it whenever
+// fields are added, removed or change meaning.
+const SchemaVersion = "70"
+
+// GeneratorVersion is set at build time with
+// -ldflags "-X goanalyzer/analyzer.GeneratorVersion=<version>".
+var GeneratorVersion = "dev"
+
+// Revision identifies the commit analyzed with -at.
+type Revision struct {
+	Ref    string `json:"ref"`
+	Commit string `json:"commit"`
+}
+
+type AnalysisResult struct {
+	SchemaVersion              string                      `json:"schemaVersion"`
+	GeneratorVersion           string                      `json:"generatorVersion"`
+	Revision                   *Revision                   `json:"revision,omitempty"`
+	Interfaces                 []InterfaceInfo             `json:"interfaces"`
+	Structs                    []StructInfo                `json:"structs"`
+	Functions                  []FunctionInfo              `json:"functions"`
+	LeakyAPIs                  []LeakyAPI                  `json:"leakyAPIs"`
+	UncalledInterfaces         []Declaration               `json:"uncalledInterfaces"`
+	InterfaceHierarchy         []InterfaceEdge             `json:"interfaceHierarchy"`
+	RedundantMethods           []RedundantMethod           `json:"redundantMethods"`
+	ReinventedInterfaces       []ReinventedInterface       `json:"reinventedInterfaces"`
+	ExternalInterfaces         []InterfaceInfo             `json:"externalInterfaces,omitempty"`
+	ErrorTypes                 []Declaration               `json:"errorTypes"`
+	IgnoredContexts            []Declaration               `json:"ignoredContexts"`
+	Conversions                []Conversion                `json:"conversions"`
+	TypeAssertions             []TypeAssertion             `json:"typeAssertions"`
+	EmptyStructUses            []EmptyStructUse            `json:"emptyStructUses"`
+	ValueCopyWarnings          []ValueCopyWarning          `json:"valueCopyWarnings"`
+	Enums                      []Enum                      `json:"enums"`
+	Instantiations             []Instantiation             `json:"instantiations"`
+	Modules                    []ModuleInfo                `json:"modules"`
+	FluentTypes                []FluentType                `json:"fluentTypes"`
+	Decorators                 []Decorator                 `json:"decorators"`
+	ExtractInterfaceCandidates []ExtractInterfaceCandidate `json:"extractInterfaceCandidates"`
+	Deprecated                 []Declaration               `json:"deprecated"`
+	Undocumented               []Declaration               `json:"undocumented"`
+	Examples                   []ExampleInfo               `json:"examples,omitempty"`
+	TooManyParams              []ParamViolation            `json:"tooManyParams,omitempty"`
+	NamingSuggestions          []NamingSuggestion          `json:"namingSuggestions,omitempty"`
+	LocalTypes                 []LocalType                 `json:"localTypes,omitempty"`
+	CallGraph                  []CallEdge                  `json:"callGraph,omitempty"`
+	Stats                      *Stats                      `json:"stats,omitempty"`
+	Errors                     []string                    `json:"errors"`
+	API                        []APIPackage                `json:"api,omitempty"`
+	LSPSymbols                 []LSPDocument               `json:"lspSymbols,omitempty"`
+	PackageImports             []PackageImports            `json:"packageImports,omitempty"`
+	Cycles                     []Cycle                     `json:"cycles,omitempty"`
+	Explained                  []TypeExplanation           `json:"-"`
+}
+
+// Options controls the optional, more expensive parts of the analysis.
+type Options struct {
+	// MethodDependencies walks method bodies to collect the packages they use.
+	MethodDependencies bool
+	// PublicAPI collects the exported surface of each package.
+	PublicAPI bool
+	// LSPSymbols outlines each file as LSP document symbols.
+	LSPSymbols bool
+	// PackageImports builds the import graph between the main module's
+	// packages, and lists the imports from outside it.
+	PackageImports bool
+	// Examples loads test files to link Example functions to declarations.
+	Examples bool
+	// Tests also reports the interfaces and structs declared in test files,
+	// checking the structs against the interfaces of the analyzed packages.
+	Tests bool
+	// SkipVendor drops packages under a vendor directory.
+	SkipVendor bool
+	// OnlyModule drops packages outside the main module.
+	OnlyModule bool
+	// Goroutines locates the go statements in each method body.
+	Goroutines bool
+	// Panics locates the calls to panic in each method body.
+	Panics bool
+	// TokenCounts counts the tokens in each method body.
+	TokenCounts bool
+	// ReturnedErrors finds the errors each method body returns.
+	ReturnedErrors bool
+	// FieldAccess adds the methods reading and writing each struct field.
+	FieldAccess bool
+	// IncludeLocals reports the types declared inside function bodies.
+	IncludeLocals bool
+	// CallGraph, one of CallGraphAlgorithms, finds the calls between the
+	// analyzed functions and methods.
+	CallGraph string
+	// IncludeSource attaches the source text of each type and method,
+	// cut to SourceMaxLines lines unless that is 0.
+	IncludeSource  bool
+	SourceMaxLines int
+	// Explain collects the types with this name, bare or qualified, into
+	// Explained.
+	Explain string
+	// MaxDepth limits the packages analyzed to those at most this many
+	// directories below the root; negative means no limit. Include and
+	// Exclude then filter the packages within that depth.
+	MaxDepth int
+	// Tags, GOOS and GOARCH select the build configuration to load.
+	Tags   string
+	GOOS   string
+	GOARCH string
+	// Overlay replaces the contents of files on disk, keyed by absolute path.
+	Overlay map[string][]byte
+	// IncludeGenerated keeps declarations from files marked
+	// "// Code generated ... DO NOT EDIT.".
+	IncludeGenerated bool
+	// StdlibTypes are checked against every interface to spot interfaces
+	// duplicating standard library ones.
+	StdlibTypes []string
+	// StructuredTypes adds a TypeRef to every parameter and return type,
+	// and to every type parameter constraint.
+	StructuredTypes bool
+	// CopyThreshold is the struct size, in bytes, above which passing a
+	// struct by value to an interface parameter is reported.
+	CopyThreshold int64
+	// ExternalInterfaces also matches structs against the interfaces
+	// exported by the packages they import.
+	ExternalInterfaces bool
+	// StdlibInterfaces also matches structs against these standard library
+	// interfaces, named like "io.Reader" or "error", whether their packages
+	// are imported or not. Matches are reported like ExternalInterfaces.
+	StdlibInterfaces []string
+	// BasicConversions also reports conversions between named and basic
+	// types.
+	BasicConversions bool
+	// Include and Exclude select packages by import path pattern; see
+	// matchPackage.
+	Include []string
+	Exclude []string
+	// Sections selects the result sections to compute, as parsed by
+	// ParseSections; nil computes all of them.
+	Sections map[string]bool
+	// CountOnly only counts interfaces and structs, skipping type
+	// information and all per-type processing.
+	CountOnly bool
+	// Workers is the number of packages analyzed at once; 0 means
+	// GOMAXPROCS.
+	Workers int
+	// CacheDir keeps results between runs, see resultCache; empty disables
+	// caching. CountOnly and Explain runs are never cached.
+	CacheDir string
+
+	// The remaining options shape the result once the packages are
+	// analyzed.
+
+	// ExportedOnly drops unexported interfaces and structs.
+	ExportedOnly bool
+	// Implements keeps the structs implementing any, or with ImplementsAll
+	// every, interface named; see filterImplementing.
+	Implements    []string
+	ImplementsAll bool
+	// MaxParams reports the methods with more parameters in TooManyParams;
+	// 0 disables.
+	MaxParams int
+	// NamingLint suggests idiomatic -er names for interfaces.
+	NamingLint bool
+	// Stats summarizes the result, with rankings cut to Top entries unless
+	// that is 0.
+	Stats bool
+	Top   int
+	// TypeStyle is how types name their package, one of TypeStyles; empty
+	// stands for relative.
+	TypeStyle string
+	// MaxMethods caps the methods listed per class in the mermaid and
+	// plantuml formats; 0 lists all.
+	MaxMethods int
+	// GroupPackages draws each package as a box in the plantuml format.
+	GroupPackages bool
+	// FailOn lists the categories, as parsed by ParseFailOn, that make
+	// Failures report a result with entries in them. MinDocCoverage makes it
+	// report doc coverage below this percentage.
+	FailOn         []string
+	MinDocCoverage float64
+}
+
+// Result is the outcome of an analysis.
+type Result = AnalysisResult
+
+// Option adjusts the Options of an Analyzer.
+type Option func(*Options)
+
+// WithOptions replaces all options with opts.
+func WithOptions(opts Options) Option {
+	return func(o *Options) {
+		*o = opts
+	}
+}
+
+// Analyzer analyzes Go modules with fixed options.
+type Analyzer struct {
+	opts Options
+}
+
+// New returns an Analyzer using the command line's defaults, adjusted by
+// opts in order.
+func New(opts ...Option) *Analyzer {
+	a := &Analyzer{opts: Options{
+		MaxDepth:       -1,
+		SourceMaxLines: 20,
+		CopyThreshold:  64,
+		StdlibTypes:    DefaultStdlibTypes,
+		Top:            10,
+	}}
+	for _, opt := range opts {
+		opt(&a.opts)
+	}
+	return a
+}
+
+// Analyze analyzes the packages in and below the directory path. Problems in
+// individual packages are listed in the result's Errors; the error is only
+// set when the packages cannot be loaded at all, and the result then holds
+// it among its Errors as well.
+func (a *Analyzer) Analyze(ctx context.Context, path string) (*Result, error) {
+	result, err := analyze(ctx, path, a.opts)
+	if err != nil {
+		return &result, err
+	}
+
+	if a.opts.ExportedOnly {
+		result.Interfaces, result.Structs = filterExported(result.Interfaces, result.Structs)
+	}
+	if a.opts.CountOnly {
+		return &result, nil
+	}
+	if len(a.opts.Implements) > 0 {
+		result.Structs = filterImplementing(result.Structs, a.opts.Implements, a.opts.ImplementsAll)
+	}
+	if a.opts.MaxParams > 0 {
+		result.TooManyParams = findTooManyParams(result, a.opts.MaxParams)
+	}
+	if a.opts.NamingLint {
+		result.NamingSuggestions = findNamingSuggestions(result.Interfaces)
+	}
+	if a.opts.Stats {
+		result.Stats = computeStats(result, a.opts.Top)
+	}
+	return &result, nil
+}
+
+// Write writes result to w in format, one of Formats, leaving out the
+// sections not selected.
+func (a *Analyzer) Write(w io.Writer, result *Result, format string) error {
+	dropSections(result, a.opts.Sections)
+	return writeResult(w, *result, format, a.opts)
+}
+
+// WriteSplit writes result into dir as one file per package in format, each
+// with its own stats.
+func (a *Analyzer) WriteSplit(dir string, result *Result, format string) error {
+	parts := splitByPackage(*result)
+	for i := range parts {
+		if a.opts.Stats {
+			parts[i].Result.Stats = computeStats(parts[i].Result, a.opts.Top)
+		}
+		dropSections(&parts[i].Result, a.opts.Sections)
+	}
+	return writeSplit(dir, parts, format, a.opts)
+}
+
+// Failures describes why result fails the FailOn and MinDocCoverage
+// options, or returns nil if it passes.
+func (a *Analyzer) Failures(result *Result) []string {
+	failed := failures(*result, a.opts.FailOn)
+	if coverage, ok := docCoverage(*result); ok && coverage < a.opts.MinDocCoverage {
+		failed = append(failed, fmt.Sprintf("doc coverage %.1f%% is below -min-doc-coverage %g%%", coverage, a.opts.MinDocCoverage))
+	}
+	return failed
+}
+
+func analyze(ctx context.Context, rootPath string, opts Options) (AnalysisResult, error) {
+	var result AnalysisResult
+	result.SchemaVersion = SchemaVersion
+	result.GeneratorVersion = GeneratorVersion
+	result.Interfaces = make([]InterfaceInfo, 0)
+	result.Structs = make([]StructInfo, 0)
+	result.Functions = make([]FunctionInfo, 0)
+	result.LeakyAPIs = make([]LeakyAPI, 0)
+	result.ErrorTypes = make([]Declaration, 0)
+	result.FluentTypes = make([]FluentType, 0)
+	result.Decorators = make([]Decorator, 0)
+	result.ExtractInterfaceCandidates = make([]ExtractInterfaceCandidate, 0)
+	result.IgnoredContexts = make([]Declaration, 0)
+	result.Conversions = make([]Conversion, 0)
+	result.TypeAssertions = make([]TypeAssertion, 0)
+	result.EmptyStructUses = make([]EmptyStructUse, 0)
+	result.ValueCopyWarnings = make([]ValueCopyWarning, 0)
+	result.Enums = make([]Enum, 0)
+	result.Instantiations = make([]Instantiation, 0)
+	result.Modules = make([]ModuleInfo, 0)
+	result.RedundantMethods = make([]RedundantMethod, 0)
+	result.Deprecated = make([]Declaration, 0)
+	result.Undocumented = make([]Declaration, 0)
+	result.UncalledInterfaces = make([]Declaration, 0)
+	result.InterfaceHierarchy = make([]InterfaceEdge, 0)
+	result.ReinventedInterfaces = make([]ReinventedInterface, 0)
+	result.Errors = make([]string, 0)
+
+	// Interface types parallel to result.Interfaces, and the interface
+	// methods called anywhere in the analyzed packages.
+	ifaceTypes := make([]*types.Interface, 0)
+	calledMethods := make(map[*types.Func]bool)
+
+	// Configure package loading
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+			packages.NeedImports | packages.NeedTypes | packages.NeedTypesInfo |
+			packages.NeedSyntax | packages.NeedModule,
+		Context: ctx,
+		Dir:     rootPath,
+		Tests:   opts.Examples || opts.Tests,
+		Overlay: opts.Overlay,
+	}
+	if opts.Tags != "" {
+		cfg.BuildFlags = append(cfg.BuildFlags, "-tags="+opts.Tags)
+	}
+	if opts.GOOS != "" || opts.GOARCH != "" {
+		cfg.Env = os.Environ()
+		if opts.GOOS != "" {
+			cfg.Env = append(cfg.Env, "GOOS="+opts.GOOS)
+		}
+		if opts.GOARCH != "" {
+			cfg.Env = append(cfg.Env, "GOARCH="+opts.GOARCH)
+		}
+	}
+
+	if opts.CountOnly {
+		cfg.Mode = countLoadMode
+		cfg.Tests = false
+	}
+
+	patterns := []string{"./..."}
+	if opts.MaxDepth >= 0 {
+		var err error
+		patterns, err = patternsWithinDepth(cfg, opts.MaxDepth)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("loading packages: %v", err))
+			return result, fmt.Errorf("loading packages: %w", err)
+		}
+	}
+
+	var diskCache *resultCache
+	if opts.CacheDir != "" && !opts.CountOnly && opts.Explain == "" {
+		var cacheErr error
+		diskCache, cacheErr = openResultCache(opts.CacheDir, cfg, patterns, opts)
+		if cacheErr != nil {
+			log.Printf("Error opening cache: %v", cacheErr)
+		} else if cached, ok := diskCache.result(); ok {
+			return cached, nil
+		}
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		log.Printf("Error loading packages: %v", err)
+		result.Errors = append(result.Errors, fmt.Sprintf("loading packages: %v", err))
+		return result, fmt.Errorf("loading packages: %w", err)
+	}
+
+	modulePath := mainModulePath(pkgs)
+	src := buildSourceIndex(pkgs)
+	cache := newTypeCache()
+	style := typeStyle(opts.TypeStyle)
+
+	var launches map[Position][]Position
+	if opts.Goroutines {
+		launches = collectGoroutineLaunches(pkgs)
+	}
+	// Results missing parts for reasons other than the packages analyzed
+	// are not cached.
+	complete := true
+	var stdlibIfaces []externalInterface
+	if len(opts.StdlibInterfaces) > 0 && len(pkgs) > 0 && !opts.CountOnly {
+		stdlibIfaces, err = loadStdlibInterfaces(ctx, opts.StdlibInterfaces, pkgs, src, style)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("loading standard library interfaces: %v", err))
+			complete = false
+		}
+	}
+	var external *externalInterfaces
+	if (opts.ExternalInterfaces || len(stdlibIfaces) > 0) && len(pkgs) > 0 {
+		external = newExternalInterfaces(pkgs[0].Fset, src, cache, style, stdlibIfaces)
+	}
+	goarch := opts.GOARCH
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	sizes := types.SizesFor("gc", goarch)
+	if sizes == nil {
+		sizes = types.SizesFor("gc", "amd64")
+	}
+	var panicked map[Position]panicSites
+	if opts.Panics {
+		panicked = collectPanicSites(pkgs)
+	}
+	var tokens map[Position]int
+	if opts.TokenCounts {
+		tokens = collectTokenCounts(pkgs, opts.Overlay)
+	}
+	var declSources *declarationSources
+	if opts.IncludeSource && !opts.CountOnly {
+		declSources = collectSources(pkgs, opts.Overlay, opts.SourceMaxLines)
+	}
+	var returnedErrors map[Position][]string
+	if opts.ReturnedErrors {
+		returnedErrors = collectReturnedErrors(pkgs, style)
+	}
+
+	// Declarations from generated files are skipped in every section;
+	// methods they add to hand-written types are kept, as they still shape
+	// those types' method sets.
+	skipObject := func(pkg *packages.Package, obj types.Object) bool {
+		if isCgoDeclaration(obj.Name()) {
+			return true
+		}
+		return !opts.IncludeGenerated && src.generated[pkg.Fset.Position(obj.Pos()).Filename]
+	}
+	// Packages with type errors are only analyzed when they use cgo, as
+	// their errors then usually stem from the C side.
+	analyzed := func(pkg *packages.Package) bool {
+		return includePackage(pkg, modulePath, opts) && !isTestVariant(pkg) &&
+			pkg.Types != nil && (len(pkg.Errors) == 0 || usesCgo(pkg))
+	}
+	var interfaceIndex []indexedInterface
+	if !opts.CountOnly {
+		interfaceIndex = buildInterfaceIndex(pkgs, analyzed, skipObject, src, style)
+	}
+
+	modules := make(map[string]*packages.Module)
+	sources := newSourceFiles(opts.Overlay)
+	instances := make(instantiations)
+	usage := make(interfaceUsage)
+
+	findSections := func(pkg *packages.Package, skip func(types.Object) bool, skipFile func(string) bool) packageSections {
+		var s packageSections
+		if !opts.CountOnly {
+			if opts.wants("functions") {
+				s.Functions = findFunctions(pkg, src, style, skip)
+			}
+			if opts.wants("leakyAPIs") {
+				s.LeakyAPIs = findLeakyAPIs(pkg, style, skip)
+			}
+			if opts.wants("undocumented") {
+				s.Undocumented = findUndocumented(pkg, src, skip)
+			}
+			if opts.wants("ignoredContexts") {
+				s.IgnoredContexts = findIgnoredContexts(pkg, skip)
+			}
+			if opts.wants("conversions") {
+				s.Conversions = findConversions(pkg, opts.BasicConversions, style, skipFile)
+			}
+			if opts.wants("typeAssertions") {
+				s.TypeAssertions = findTypeAssertions(pkg, style, skipFile)
+			}
+			if opts.wants("emptyStructUses") {
+				s.EmptyStructUses = findEmptyStructUses(pkg, style, skipFile)
+			}
+			if opts.wants("valueCopyWarnings") {
+				s.ValueCopyWarnings = findValueCopies(pkg, sizes, opts.CopyThreshold, style, skipFile)
+			}
+			if opts.wants("enums") {
+				s.Enums = findEnums(pkg, skipFile)
+			}
+			if opts.wants("cycles") {
+				s.Cycles = findTypeCycles(pkg, skip)
+			}
+		}
+		if opts.PublicAPI {
+			api := buildPackageAPI(pkg, style, skip)
+			s.API = &api
+		}
+		if opts.LSPSymbols {
+			s.LSPSymbols = buildLSPDocuments(pkg, sources, style, skipFile)
+		}
+		return s
+	}
+
+	// analyzePackage records what pkg contributes to the result in a.
+	analyzePackage := func(pkg *packages.Package, a *packageAnalysis) {
+		part := &a.result
+		if !includePackage(pkg, modulePath, opts) {
+			return
+		}
+		if pkg.Module != nil {
+			a.module = pkg.Module
+		}
+
+		// Test variants repeat the declarations of the package under test,
+		// so only their examples and the types of their test files are of
+		// interest.
+		if isTestVariant(pkg) {
+			if opts.Examples && len(pkg.Errors) == 0 {
+				part.Examples = append(part.Examples, extractExamples(pkg, opts.IncludeGenerated)...)
+			}
+			if opts.Tests && !opts.CountOnly && isTestPackage(pkg) && len(pkg.Errors) == 0 {
+				ifaces, ifaceTypes, structs := findTestTypes(pkg, interfaceIndex, src, cache, style, func(obj types.Object) bool {
+					return skipObject(pkg, obj)
+				})
+				part.Interfaces = append(part.Interfaces, ifaces...)
+				a.ifaceTypes = append(a.ifaceTypes, ifaceTypes...)
+				part.Structs = append(part.Structs, structs...)
+			}
+			return
+		}
+
+		skip := func(obj types.Object) bool {
+			return skipObject(pkg, obj)
+		}
+
+		skipFile := func(filename string) bool {
+			return !opts.IncludeGenerated && src.generated[filename]
+		}
+
+		// Import cycles are package errors, so the graph is built before
+		// packages with errors are left out.
+		if (opts.PackageImports || opts.wants("cycles")) && (modulePath == "" || pkg.PkgPath == modulePath || strings.HasPrefix(pkg.PkgPath, modulePath+"/")) {
+			part.PackageImports = append(part.PackageImports, collectPackageImports(pkg, skip))
+		}
+
+		cgo := usesCgo(pkg)
+		if len(pkg.Errors) > 0 {
+			for _, err := range pkg.Errors {
+				log.Printf("Error in package %s: %v", pkg.PkgPath, err)
+				part.Errors = append(part.Errors, fmt.Sprintf("package %s: %v", pkg.PkgPath, err))
+			}
+			// Type errors in cgo packages usually stem from the C side only,
+			// so keep reporting whatever pure-Go types were checked.
+			if !cgo || pkg.Types == nil {
+				return
+			}
+		}
+		if cgo {
+			part.Errors = append(part.Errors, fmt.Sprintf("package %s: cgo declarations elided, only Go types are reported", pkg.PkgPath))
+		}
+
+		local, cached := packageSections{}, false
+		if diskCache != nil {
+			local, cached = diskCache.sections(pkg)
+		}
+		if !cached {
+			local = findSections(pkg, skip, skipFile)
+			if diskCache != nil {
+				diskCache.storeSections(pkg, local)
+			}
+		}
+		local.addTo(part)
+
+		if !opts.CountOnly {
+			if opts.wants("instantiations") {
+				collectInstantiations(pkg, a.instances, style, skipFile)
+			}
+			if opts.wants("uncalledInterfaces") {
+				collectInterfaceCalls(pkg, a.calledMethods)
+			}
+			if opts.wants("interfaces") {
+				collectInterfaceUsage(pkg, a.usage, skipFile)
+			}
+		}
+
+		var methodDecls map[*types.TypeName][]*ast.FuncDecl
+		if (opts.MethodDependencies || opts.FieldAccess) && !opts.CountOnly {
+			methodDecls = collectMethodDecls(pkg)
+		}
+
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			// Variables of interface and struct types are not types.
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || skip(obj) {
+				continue
+			}
+
+			if opts.Explain != "" && matchesExplain(obj, opts.Explain) {
+				part.Explained = append(part.Explained, explainType(obj, pkg, src, style))
+			}
+
+			switch t := obj.Type().Underlying().(type) {
+			case *types.Interface:
+				if t.NumMethods() > 0 {
+					if opts.CountOnly {
+						part.Interfaces = append(part.Interfaces, InterfaceInfo{Name: obj.Name(), Package: pkg.PkgPath})
+						continue
+					}
+					iface := processInterface(obj, pkg, src, style)
+					if iface != nil {
+						if opts.StructuredTypes {
+							addTypeRefs(iface.Methods, obj.Type(), pkg.Types)
+							addConstraintRefs(iface.TypeParams, obj.Type())
+						}
+						if declSources != nil {
+							iface.Source = declSources.types[iface.Position.line()]
+							declSources.addToMethods(iface.Methods)
+						}
+						part.Interfaces = append(part.Interfaces, *iface)
+						a.ifaceTypes = append(a.ifaceTypes, t)
+						if named, ok := obj.Type().(*types.Named); ok {
+							if methods := fluentMethods(named); len(methods) > 0 {
+								part.FluentTypes = append(part.FluentTypes, FluentType{
+									Type:    Declaration{Name: iface.Name, Package: iface.Package, Position: iface.Position},
+									Methods: methods,
+								})
+							}
+						}
+					}
+				}
+			case *types.Struct:
+				if opts.CountOnly {
+					if _, ok := obj.Type().(*types.Named); ok {
+						part.Structs = append(part.Structs, StructInfo{Name: obj.Name(), Package: pkg.PkgPath})
+					}
+					continue
+				}
+				strct := processStruct(obj, pkg, interfaceIndex, src, cache, style)
+				if strct != nil {
+					if external != nil {
+						var imports []*types.Package
+						if opts.ExternalInterfaces {
+							imports = pkg.Types.Imports()
+						}
+						external.match(strct, obj.Type().(*types.Named), imports)
+					}
+					if opts.StructuredTypes {
+						addTypeRefs(strct.Methods, obj.Type(), pkg.Types)
+						addConstraintRefs(strct.TypeParams, obj.Type())
+					}
+					if opts.MethodDependencies {
+						strct.MethodDependencies = methodDependencies(methodDecls[obj], pkg)
+					}
+					if opts.FieldAccess {
+						fieldAccess(strct.Fields, t, methodDecls[obj], pkg)
+					}
+					if opts.wants("decorators") {
+						part.Decorators = append(part.Decorators, findDecorators(obj, t, pkg.Fset, cache)...)
+					}
+					if opts.Goroutines {
+						for i := range strct.Methods {
+							strct.Methods[i].GoroutineLaunches = launches[strct.Methods[i].Position.line()]
+						}
+					}
+					if opts.Panics {
+						for i := range strct.Methods {
+							sites := panicked[strct.Methods[i].Position.line()]
+							strct.Methods[i].Panics = sites.panics
+							strct.Methods[i].Repanics = sites.repanics
+						}
+					}
+					if opts.TokenCounts {
+						for i := range strct.Methods {
+							strct.Methods[i].TokenCount = tokens[strct.Methods[i].Position.line()]
+						}
+					}
+					if opts.ReturnedErrors {
+						for i := range strct.Methods {
+							strct.Methods[i].ReturnedErrorTypes = returnedErrors[strct.Methods[i].Position.line()]
+						}
+					}
+					if declSources != nil {
+						strct.Source = declSources.types[strct.Position.line()]
+						declSources.addToMethods(strct.Methods)
+					}
+					part.Structs = append(part.Structs, *strct)
+					if methods := fluentMethods(obj.Type().(*types.Named)); len(methods) > 0 {
+						part.FluentTypes = append(part.FluentTypes, FluentType{
+							Type:    Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position},
+							Methods: methods,
+						})
+					}
+					if implementsError(obj.Type()) {
+						part.ErrorTypes = append(part.ErrorTypes, Declaration{
+							Name:     strct.Name,
+							Package:  strct.Package,
+							Position: strct.Position,
+						})
+					}
+				}
+			}
+		}
+
+		if opts.IncludeLocals && !opts.CountOnly {
+			part.LocalTypes = append(part.LocalTypes, findLocalTypes(pkg, interfaceIndex, src, cache, style, skipFile)...)
+		}
+	}
+
+	// Packages are analyzed concurrently, each into its own packageAnalysis,
+	// and merged in load order so that the result does not depend on
+	// scheduling.
+	analyses := make([]*packageAnalysis, len(pkgs))
+	forEachPackage(ctx, len(pkgs), opts.Workers, func(i int) {
+		a := newPackageAnalysis()
+		analyzePackage(pkgs[i], a)
+		analyses[i] = a
+	})
+	for i, a := range analyses {
+		if a == nil {
+			complete = false
+			result.Errors = append(result.Errors, fmt.Sprintf("analysis stopped after %d of %d packages: %v; results are partial", i, len(pkgs), ctx.Err()))
+			break
+		}
+		if a.module != nil {
+			modules[a.module.Path] = a.module
+		}
+		a.mergeInto(&result, &ifaceTypes, calledMethods, instances, usage)
+	}
+
+	if opts.CountOnly {
+		sortResult(&result)
+		return result, nil
+	}
+
+	for i := range result.Interfaces {
+		result.Interfaces[i].UsageKinds = usage.kinds(result.Interfaces[i])
+	}
+	// Functions come from the per-package sections, which may be cached, so
+	// the results of the body passes are added to them here.
+	for i := range result.Functions {
+		fn := &result.Functions[i]
+		key := fn.Position.line()
+		if opts.Goroutines {
+			fn.GoroutineLaunches = launches[key]
+		}
+		if opts.Panics {
+			fn.Panics, fn.Repanics = panicked[key].panics, panicked[key].repanics
+		}
+		if opts.TokenCounts {
+			fn.TokenCount = tokens[key]
+		}
+		if opts.ReturnedErrors {
+			fn.ReturnedErrorTypes = returnedErrors[key]
+		}
+		if declSources != nil {
+			fn.Source = declSources.funcs[key]
+		}
+	}
+	result.UncalledInterfaces = findUncalledInterfaces(result.Interfaces, ifaceTypes, calledMethods)
+	result.InterfaceHierarchy = buildInterfaceHierarchy(result.Interfaces, ifaceTypes)
+	if opts.wants("redundantMethods") && len(pkgs) > 0 {
+		result.RedundantMethods = findRedundantMethods(result.Interfaces, ifaceTypes, pkgs[0].Fset)
+	}
+	if len(opts.StdlibTypes) > 0 && opts.wants("reinventedInterfaces") {
+		candidates, err := loadStdlibTypes(ctx, opts.StdlibTypes)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("loading standard library types: %v", err))
+			complete = false
+		} else {
+			result.ReinventedInterfaces = findReinventedInterfaces(result.Interfaces, ifaceTypes, candidates)
+		}
+	}
+	if opts.wants("modules") {
+		result.Modules = collectModules(modules)
+	}
+	if opts.wants("instantiations") && len(pkgs) > 0 {
+		result.Instantiations = instances.list(pkgs[0].Fset)
+	}
+	if external != nil {
+		result.ExternalInterfaces = external.interfaces()
+	}
+	if opts.CallGraph != "" && opts.wants("callGraph") {
+		edges, err := buildCallGraph(pkgs, analyzed, opts.CallGraph)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("building call graph: %v", err))
+			complete = false
+		} else {
+			result.CallGraph = edges
+		}
+	}
+	result.Deprecated = collectDeprecated(result)
+	if opts.wants("extractInterfaceCandidates") && !opts.CountOnly {
+		result.ExtractInterfaceCandidates = findExtractInterfaceCandidates(result)
+	}
+	sort.Slice(result.API, func(i, j int) bool {
+		return result.API[i].Path < result.API[j].Path
+	})
+	sortLSPDocuments(result.LSPSymbols)
+	finishPackageImports(result.PackageImports)
+	if opts.wants("cycles") && !opts.CountOnly {
+		result.Cycles = append(result.Cycles, findImportCycles(result.PackageImports, pkgs)...)
+		sort.Slice(result.Cycles, func(i, j int) bool {
+			return cycleKey(result.Cycles[i]) < cycleKey(result.Cycles[j])
+		})
+	}
+	if !opts.PackageImports {
+		result.PackageImports = nil
+	}
+	sortResult(&result)
+
+	if diskCache != nil && complete {
+		diskCache.storeResult(result)
+	}
+	return result, nil
+}
+
+func processInterface(obj types.Object, pkg *packages.Package, src *sourceIndex, style typeStyle) *InterfaceInfo {
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	pos := pkg.Fset.Position(obj.Pos())
+	info := &InterfaceInfo{
+		ID:       typeID(obj),
+		Name:     obj.Name(),
+		Package:  pkg.PkgPath,
+		Position: src.position(pkg.Fset, obj.Pos()),
+		Methods:  make([]MethodInfo, 0),
+	}
+	if named, ok := obj.Type().(*types.Named); ok {
+		info.TypeParams = typeParams(named.TypeParams(), pkg.Types, style)
+	}
+	info.Doc = src.doc(obj.Pos())
+	info.DeprecationNote, info.Deprecated = src.deprecation(obj.Pos())
+	info.BuildConstraints = src.constraints[pos.Filename]
+
+	explicit := make(map[*types.Func]bool)
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		explicit[iface.ExplicitMethod(i)] = true
+	}
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		methodPos := pkg.Fset.Position(method.Pos())
+		signature := method.Type().(*types.Signature)
+
+		methodInfo := MethodInfo{
+			ID:              methodID(info.ID, method),
+			Name:            method.Name(),
+			Position:        src.position(pkg.Fset, method.Pos()),
+			Receiver:        receiverDeclaration(method, pkg.Fset),
+			Parameters:      extractParams(signature, method.Pkg(), style),
+			ReturnTypes:     extractReturnTypes(signature, method.Pkg(), style),
+			Signature:       canonicalTypes(signature),
+			ParamCount:      signature.Params().Len(),
+			Variadic:        signature.Variadic(),
+			ReturnCount:     signature.Results().Len(),
+			ImplementedFrom: make([]Declaration, 0),
+		}
+		methodInfo.Doc = src.doc(method.Pos())
+		methodInfo.DeprecationNote, methodInfo.Deprecated = src.deprecation(method.Pos())
+		methodInfo.BuildConstraints = src.constraints[methodPos.Filename]
+		methodInfo.Promoted = !explicit[method]
+		info.Methods = append(info.Methods, methodInfo)
+	}
+
+	return info
+}
+
+func processStruct(obj types.Object, pkg *packages.Package, interfaces []indexedInterface, src *sourceIndex, cache *typeCache, style typeStyle) *StructInfo {
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	strct, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	pos := pkg.Fset.Position(obj.Pos())
+	info := &StructInfo{
+		ID:                    typeID(obj),
+		Name:                  obj.Name(),
+		Package:               pkg.PkgPath,
+		Position:              src.position(pkg.Fset, obj.Pos()),
+		Methods:               make([]MethodInfo, 0),
+		EmbeddedTypes:         make([]string, 0),
+		IsEmpty:               strct.NumFields() == 0,
+		FieldCount:            strct.NumFields(),
+		EmbeddingDepth:        embeddingDepth(named),
+		TypeParams:            typeParams(named.TypeParams(), pkg.Types, style),
+		ImplementedInterfaces: make([]Declaration, 0),
+	}
+	info.Fields = structFields(info.ID, strct, pkg, src, style)
+	info.Doc = src.doc(obj.Pos())
+	info.DeprecationNote, info.Deprecated = src.deprecation(obj.Pos())
+	info.BuildConstraints = src.constraints[pos.Filename]
+
+	// Get embedded types and fields holding functions
+	for i, field := range info.Fields {
+		if field.Embedded {
+			info.EmbeddedTypes = append(info.EmbeddedTypes, field.Type)
+			info.Embedded = append(info.Embedded, embeddedType(strct.Field(i).Type(), field.Type))
+		}
+		if field.Signature != "" {
+			info.FunctionFields = append(info.FunctionFields, field)
+		}
+	}
+
+	// Get methods from both value and pointer receivers
+	methodSet := cache.methodSet(named)
+	ptrMethodSet := cache.methodSet(types.NewPointer(named))
+
+	// Helper function to process method sets
+	processMethodSet := func(ms *types.MethodSet) {
+		for i := 0; i < ms.Len(); i++ {
+			sel := ms.At(i)
+			method := sel.Obj().(*types.Func)
+			methodPos := pkg.Fset.Position(method.Pos())
+			signature := method.Type().(*types.Signature)
+
+			// Skip if method already exists
+			methodExists := false
+			for _, existingMethod := range info.Methods {
+				if existingMethod.Name == method.Name() {
+					methodExists = true
+					break
+				}
+			}
+			if methodExists {
+				continue
+			}
+
+			methodInfo := MethodInfo{
+				ID:              methodID(info.ID, method),
+				Name:            method.Name(),
+				Position:        src.position(pkg.Fset, method.Pos()),
+				Receiver:        receiverDeclaration(method, pkg.Fset),
+				Parameters:      extractParams(signature, method.Pkg(), style),
+				ReturnTypes:     extractReturnTypes(signature, method.Pkg(), style),
+				Signature:       canonicalTypes(signature),
+				ParamCount:      signature.Params().Len(),
+				Variadic:        signature.Variadic(),
+				ReturnCount:     signature.Results().Len(),
+				ImplementedFrom: make([]Declaration, 0),
+			}
+			methodInfo.Doc = src.doc(method.Pos())
+			methodInfo.DeprecationNote, methodInfo.Deprecated = src.deprecation(method.Pos())
+			methodInfo.BuildConstraints = src.constraints[methodPos.Filename]
+
+			methodInfo.Accessor = accessorKind(method)
+
+			// Methods declared on the struct itself may shadow a method
+			// promoted from an embedded field.
+			methodInfo.Promoted = len(sel.Index()) > 1
+			methodInfo.PromotedThroughPointer = promotedThroughPointer(sel.Recv(), sel.Index())
+			if !methodInfo.Promoted {
+				if shadowed := findOverridden(strct, method); shadowed != nil {
+					methodInfo.Overrides = overriddenDeclaration(shadowed, pkg)
+				}
+			}
+			info.Methods = append(info.Methods, methodInfo)
+		}
+	}
+
+	// Process both value and pointer receiver methods
+	processMethodSet(methodSet)
+	processMethodSet(ptrMethodSet)
+
+	// Check interface implementations, by both pointer and value receivers.
+	// Generic interfaces are implemented for the type arguments the
+	// struct's methods imply, if any.
+	subject := genericSubject(named)
+	for _, indexed := range interfaces {
+		if indexed.generic == nil {
+			if cache.implements(subject, indexed.iface) {
+				addImplemented(info, indexed.info, receiverKind(subject, indexed.iface, cache))
+			}
+			continue
+		}
+		if args, ok := inferImplemented(subject, indexed.generic, cache); ok {
+			implemented := addImplemented(info, indexed.info, receiverKind(subject, indexed.iface, cache))
+			for _, arg := range args {
+				implemented.TypeArguments = append(implemented.TypeArguments, style.typeString(arg, pkg.Types))
+			}
+		}
+	}
+
+	return info
+}
+
+// addImplemented records that strct implements iface with the receiver
+// kind given, and returns the record.
+func addImplemented(strct *StructInfo, iface InterfaceInfo, kind string) *Declaration {
+	strct.ImplementedInterfaces = append(strct.ImplementedInterfaces, Declaration{
+		Name:         iface.Name,
+		Package:      iface.Package,
+		Position:     iface.Position,
+		ReceiverKind: kind,
+	})
+	if similarity, ok := methodOrderSimilarity(strct, iface); ok {
+		strct.InterfaceOrder = append(strct.InterfaceOrder, InterfaceOrder{
+			Interface:  interfaceDeclaration(iface),
+			Similarity: similarity,
+		})
+	}
+
+	// Update method implementation info
+	for i := range strct.Methods {
+		method := &strct.Methods[i]
+		for _, ifaceMethod := range iface.Methods {
+			if method.Name == ifaceMethod.Name {
+				method.ImplementedFrom = append(method.ImplementedFrom, Declaration{
+					Name:     iface.Name + "." + ifaceMethod.Name,
+					Package:  iface.Package,
+					Position: ifaceMethod.Position,
+				})
+			}
+		}
+	}
+	return &strct.ImplementedInterfaces[len(strct.ImplementedInterfaces)-1]
+}
+
+// extractParams describes the parameters of signature, declared in from.
+func extractParams(signature *types.Signature, from *types.Package, style typeStyle) []ParamInfo {
+	params := make([]ParamInfo, 0)
+	for i := 0; i < signature.Params().Len(); i++ {
+		param := signature.Params().At(i)
+		params = append(params, ParamInfo{
+			Name: param.Name(),
+			Type: style.typeString(param.Type(), from),
+		})
+	}
+	return params
+}
+
+func extractReturnTypes(signature *types.Signature, from *types.Package, style typeStyle) []string {
+	results := make([]string, 0)
+	for i := 0; i < signature.Results().Len(); i++ {
+		result := signature.Results().At(i)
+		results = append(results, style.typeString(result.Type(), from))
+	}
+	return results
+}
+
+// makeRelativePath returns path relative to the root of its module, the
+// nearest directory above it holding a go.mod file, with forward slashes.
+// Paths outside any module are kept whole.
+func makeRelativePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if root := moduleRoot(filepath.Dir(path)); root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// moduleRoots caches the module root of each directory looked up, or "" for
+// directories outside any module.
+var moduleRoots sync.Map
+
+func moduleRoot(dir string) string {
+	if root, ok := moduleRoots.Load(dir); ok {
+		return root.(string)
+	}
+	root := ""
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = moduleRoot(parent)
+	}
+	moduleRoots.Store(dir, root)
+	return root
+}
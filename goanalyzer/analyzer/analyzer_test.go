@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"context"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// fixture is the module the tests and benchmarks analyze.
+const fixture = "../../go-analyzer-test"
+
+var fixturePackages struct {
+	once sync.Once
+	pkgs map[string]*packages.Package
+	err  error
+}
+
+// fixturePackage returns the fixture package with import path
+// go-analyzer-test/internal/<name>, type-checked. The packages are loaded
+// once for all tests.
+func fixturePackage(t *testing.T, name string) *packages.Package {
+	t.Helper()
+	fixturePackages.once.Do(func() {
+		cfg := &packages.Config{
+			Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedSyntax,
+			Dir:  fixture,
+		}
+		pkgs, err := packages.Load(cfg, "./...")
+		if err != nil {
+			fixturePackages.err = err
+			return
+		}
+		fixturePackages.pkgs = make(map[string]*packages.Package)
+		for _, pkg := range pkgs {
+			fixturePackages.pkgs[pkg.PkgPath] = pkg
+		}
+	})
+	if fixturePackages.err != nil {
+		t.Fatal(fixturePackages.err)
+	}
+	pkg, ok := fixturePackages.pkgs["go-analyzer-test/internal/"+name]
+	if !ok {
+		t.Fatalf("no fixture package %s", name)
+	}
+	return pkg
+}
+
+// fixtureType returns the named type name of the fixture package pkg.
+func fixtureType(t *testing.T, pkg *packages.Package, name string) *types.Named {
+	t.Helper()
+	obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		t.Fatalf("no type %s in %s", name, pkg.PkgPath)
+	}
+	return obj.Type().(*types.Named)
+}
+
+// fixtureStruct returns the struct name of package pkgPath from result.
+func fixtureStruct(t *testing.T, result *Result, pkgPath, name string) StructInfo {
+	t.Helper()
+	for _, strct := range result.Structs {
+		if strct.Package == pkgPath && strct.Name == name {
+			return strct
+		}
+	}
+	t.Fatalf("no struct %s.%s reported", pkgPath, name)
+	return StructInfo{}
+}
+
+// writeModule writes files, by name, to a temporary directory and returns
+// it.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// BenchmarkAnalyze analyzes the fixture module with every section, and with
+// the interfaces of imported packages and the standard library matched too,
+// which checks each struct against many more interfaces.
+func BenchmarkAnalyze(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts Option
+	}{
+		{"all", func(opts *Options) {}},
+		{"external", func(opts *Options) {
+			opts.ExternalInterfaces = true
+			opts.StdlibInterfaces = DefaultStdlibInterfaces
+		}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			az := New(bm.opts)
+			for i := 0; i < b.N; i++ {
+				result, err := az.Analyze(context.Background(), fixture)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(result.Structs) == 0 {
+					b.Fatal("no structs found")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type APIValue struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Value    string   `json:"value,omitempty"`
+	Position Position `json:"position"`
+}
+
+type APIFunc struct {
+	Name      string   `json:"name"`
+	Signature string   `json:"signature"`
+	Position  Position `json:"position"`
+}
+
+type APIField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Embedded bool   `json:"embedded,omitempty"`
+}
+
+type APIType struct {
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Underlying string     `json:"underlying,omitempty"`
+	Position   Position   `json:"position"`
+	Fields     []APIField `json:"fields,omitempty"`
+	Constants  []APIValue `json:"constants,omitempty"`
+	Variables  []APIValue `json:"variables,omitempty"`
+	Functions  []APIFunc  `json:"functions,omitempty"`
+	Methods    []APIFunc  `json:"methods,omitempty"`
+}
+
+type APIPackage struct {
+	Path      string     `json:"path"`
+	Name      string     `json:"name"`
+	Constants []APIValue `json:"constants"`
+	Variables []APIValue `json:"variables"`
+	Functions []APIFunc  `json:"functions"`
+	Types     []APIType  `json:"types"`
+}
+
+// APIReport is the output of -format api: the exported surface of every
+// package, grouped the way go doc groups it.
+type APIReport struct {
+	SchemaVersion    string       `json:"schemaVersion"`
+	GeneratorVersion string       `json:"generatorVersion"`
+	Packages         []APIPackage `json:"packages"`
+}
+
+func buildPackageAPI(pkg *packages.Package, style typeStyle, skip func(types.Object) bool) APIPackage {
+	api := APIPackage{
+		Path:      pkg.PkgPath,
+		Name:      pkg.Name,
+		Constants: make([]APIValue, 0),
+		Variables: make([]APIValue, 0),
+		Functions: make([]APIFunc, 0),
+		Types:     make([]APIType, 0),
+	}
+	qualifier := style.qualifier(pkg.Types)
+
+	position := func(obj types.Object) Position {
+		return newPosition(pkg.Fset, obj.Pos(), token.NoPos)
+	}
+
+	// Like go doc, constants, variables and constructors whose type is
+	// declared in this package are listed under that type.
+	typeIndex := make(map[*types.TypeName]int)
+	owner := func(t types.Type) (int, bool) {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		named, ok := t.(*types.Named)
+		if !ok {
+			return 0, false
+		}
+		i, ok := typeIndex[named.Origin().Obj()]
+		return i, ok
+	}
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || skip(obj) {
+			continue
+		}
+		typeIndex[obj] = len(api.Types)
+		api.Types = append(api.Types, buildAPIType(obj, qualifier, position))
+	}
+
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() || skip(obj) {
+			continue
+		}
+
+		switch obj := obj.(type) {
+		case *types.Const:
+			value := APIValue{
+				Name:     obj.Name(),
+				Type:     types.TypeString(obj.Type(), qualifier),
+				Value:    obj.Val().ExactString(),
+				Position: position(obj),
+			}
+			if i, ok := owner(obj.Type()); ok {
+				api.Types[i].Constants = append(api.Types[i].Constants, value)
+			} else {
+				api.Constants = append(api.Constants, value)
+			}
+		case *types.Var:
+			value := APIValue{
+				Name:     obj.Name(),
+				Type:     types.TypeString(obj.Type(), qualifier),
+				Position: position(obj),
+			}
+			if i, ok := owner(obj.Type()); ok {
+				api.Types[i].Variables = append(api.Types[i].Variables, value)
+			} else {
+				api.Variables = append(api.Variables, value)
+			}
+		case *types.Func:
+			fn := APIFunc{
+				Name:      obj.Name(),
+				Signature: types.ObjectString(obj, qualifier),
+				Position:  position(obj),
+			}
+			results := obj.Type().(*types.Signature).Results()
+			if results.Len() > 0 {
+				if i, ok := owner(results.At(0).Type()); ok {
+					api.Types[i].Functions = append(api.Types[i].Functions, fn)
+					continue
+				}
+			}
+			api.Functions = append(api.Functions, fn)
+		}
+	}
+
+	return api
+}
+
+func buildAPIType(obj *types.TypeName, qualifier types.Qualifier, position func(types.Object) Position) APIType {
+	apiType := APIType{
+		Name:     obj.Name(),
+		Position: position(obj),
+	}
+
+	if obj.IsAlias() {
+		apiType.Kind = "alias"
+		apiType.Underlying = types.TypeString(obj.Type(), qualifier)
+		return apiType
+	}
+
+	switch underlying := obj.Type().Underlying().(type) {
+	case *types.Struct:
+		apiType.Kind = "struct"
+		for i := 0; i < underlying.NumFields(); i++ {
+			field := underlying.Field(i)
+			if !field.Exported() {
+				continue
+			}
+			apiType.Fields = append(apiType.Fields, APIField{
+				Name:     field.Name(),
+				Type:     types.TypeString(field.Type(), qualifier),
+				Embedded: field.Anonymous(),
+			})
+		}
+	case *types.Interface:
+		apiType.Kind = "interface"
+		for i := 0; i < underlying.NumExplicitMethods(); i++ {
+			method := underlying.ExplicitMethod(i)
+			if !method.Exported() {
+				continue
+			}
+			apiType.Methods = append(apiType.Methods, APIFunc{
+				Name:      method.Name(),
+				Signature: types.ObjectString(method, qualifier),
+				Position:  position(method),
+			})
+		}
+	default:
+		apiType.Kind = "other"
+		apiType.Underlying = types.TypeString(underlying, qualifier)
+	}
+
+	if named, ok := obj.Type().(*types.Named); ok {
+		for i := 0; i < named.NumMethods(); i++ {
+			method := named.Method(i)
+			if !method.Exported() {
+				continue
+			}
+			apiType.Methods = append(apiType.Methods, APIFunc{
+				Name:      method.Name(),
+				Signature: types.ObjectString(method, qualifier),
+				Position:  position(method),
+			})
+		}
+	}
+	sort.Slice(apiType.Methods, func(i, j int) bool {
+		return apiType.Methods[i].Name < apiType.Methods[j].Name
+	})
+
+	return apiType
+}
@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeAssertion is an x.(T) expression. Safe is set for the comma-ok form,
+// v, ok := x.(T), which cannot panic; the single-result form panics when x
+// does not hold a T.
+type TypeAssertion struct {
+	Package  string   `json:"package"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Safe     bool     `json:"safe"`
+	Position Position `json:"position"`
+}
+
+// findTypeAssertions returns the type assertions in pkg. Type switches
+// cannot panic and are left out. Test files are not part of the packages
+// analyzed, so assertions in tests are not reported.
+func findTypeAssertions(pkg *packages.Package, style typeStyle, skipFile func(filename string) bool) []TypeAssertion {
+	assertions := make([]TypeAssertion, 0)
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+
+		// The comma-ok form only occurs as the sole value assigned to,
+		// or declaring, two variables.
+		commaOK := make(map[*ast.TypeAssertExpr]bool)
+		markCommaOK := func(lhs int, rhs []ast.Expr) {
+			if lhs != 2 || len(rhs) != 1 {
+				return
+			}
+			if assert, ok := astutil.Unparen(rhs[0]).(*ast.TypeAssertExpr); ok {
+				commaOK[assert] = true
+			}
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				markCommaOK(len(n.Lhs), n.Rhs)
+			case *ast.ValueSpec:
+				markCommaOK(len(n.Names), n.Values)
+			}
+			return true
+		})
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			assert, ok := n.(*ast.TypeAssertExpr)
+			if !ok || assert.Type == nil {
+				return true
+			}
+			from, to := pkg.TypesInfo.TypeOf(assert.X), pkg.TypesInfo.TypeOf(assert.Type)
+			if from == nil || to == nil {
+				return true
+			}
+
+			assertions = append(assertions, TypeAssertion{
+				Package:  pkg.PkgPath,
+				From:     style.typeString(from, pkg.Types),
+				To:       style.typeString(to, pkg.Types),
+				Safe:     commaOK[assert],
+				Position: newPosition(pkg.Fset, assert.Pos(), assert.End()),
+			})
+			return true
+		})
+	}
+	return assertions
+}
+
+// unsafeAssertions counts the single-result type assertions.
+func unsafeAssertions(assertions []TypeAssertion) int {
+	count := 0
+	for _, assertion := range assertions {
+		if !assertion.Safe {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// forEachFuncBody calls fn for every function and method declaration with a
+// body in pkgs, with the position of the declared name. Positions match those
+// recorded in MethodInfo, so the results of a body pass can be looked up by
+// method. Each position is visited once, even when test variants repeat a
+// package's files.
+func forEachFuncBody(pkgs []*packages.Package, fn func(pkg *packages.Package, key Position, decl *ast.FuncDecl)) {
+	seen := make(map[Position]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+
+				namePos := pkg.Fset.Position(funcDecl.Name.Pos())
+				key := Position{
+					Path: makeRelativePath(namePos.Filename),
+					Line: namePos.Line,
+				}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				fn(pkg, key, funcDecl)
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// typeCache memoizes the method sets computed for every struct within one
+// run. It is safe for concurrent use.
+//
+// Implements checks are not memoized: each struct is checked against each
+// interface about once, and BenchmarkAnalyze runs no faster with a cache
+// of them.
+type typeCache struct {
+	methodSets typeutil.MethodSetCache
+}
+
+func newTypeCache() *typeCache {
+	return &typeCache{}
+}
+
+// methodSet returns the method set of t.
+func (c *typeCache) methodSet(t types.Type) *types.MethodSet {
+	return c.methodSets.MethodSet(t)
+}
+
+// implements reports whether t, or a pointer to it, implements iface.
+func (c *typeCache) implements(t types.Type, iface *types.Interface) bool {
+	return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+}
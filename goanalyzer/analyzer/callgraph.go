@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// CallGraphAlgorithms are the algorithms Options.CallGraph accepts: class
+// hierarchy analysis, which takes every method of a matching type as a
+// possible callee of a dynamic call, and rapid type analysis, which only
+// takes those of types the analyzed code converts to an interface.
+var CallGraphAlgorithms = []string{"cha", "rta"}
+
+// CallEdge is a call from one function or method to another, both named by
+// their declaration IDs. Calls within function literals are attributed to
+// the enclosing declaration. Dynamic calls, through an interface or a
+// function value, have an edge to each possible callee.
+type CallEdge struct {
+	Caller string `json:"caller"`
+	Callee string `json:"callee"`
+	// Package is the caller's package, and Position the call site.
+	Package  string   `json:"package"`
+	Position Position `json:"position"`
+	Dynamic  bool     `json:"dynamic,omitempty"`
+}
+
+// buildCallGraph returns the calls between the functions and methods of the
+// analyzed packages, found with algorithm. Packages outside them are built
+// from export data only, so calls through them, such as callbacks passed to
+// the standard library, are not seen.
+func buildCallGraph(pkgs []*packages.Package, analyzed func(*packages.Package) bool, algorithm string) ([]CallEdge, error) {
+	if len(pkgs) == 0 {
+		return make([]CallEdge, 0), nil
+	}
+	fset := pkgs[0].Fset
+	prog := ssa.NewProgram(fset, ssa.InstantiateGenerics)
+
+	// The analyzed packages are built from syntax, after every package
+	// they import, directly or not, is known to the program.
+	withSyntax := make(map[*types.Package]*packages.Package)
+	for _, pkg := range pkgs {
+		if analyzed(pkg) && len(pkg.Errors) == 0 {
+			withSyntax[pkg.Types] = pkg
+		}
+	}
+	created := make(map[*types.Package]bool)
+	var create func(tpkg *types.Package)
+	create = func(tpkg *types.Package) {
+		if created[tpkg] {
+			return
+		}
+		created[tpkg] = true
+		for _, dep := range tpkg.Imports() {
+			create(dep)
+		}
+		if pkg := withSyntax[tpkg]; pkg != nil {
+			prog.CreatePackage(tpkg, pkg.Syntax, pkg.TypesInfo, true)
+		} else {
+			prog.CreatePackage(tpkg, nil, nil, true)
+		}
+	}
+	for tpkg := range withSyntax {
+		create(tpkg)
+	}
+	prog.Build()
+
+	var graph *callgraph.Graph
+	switch algorithm {
+	case "cha":
+		graph = cha.CallGraph(prog)
+	case "rta":
+		// Libraries have no main function, so every function of the
+		// analyzed packages is taken to be called from outside.
+		var roots []*ssa.Function
+		for fn := range ssautil.AllFunctions(prog) {
+			if fn.Pkg != nil && withSyntax[fn.Pkg.Pkg] != nil && fn.Synthetic == "" {
+				roots = append(roots, fn)
+			}
+		}
+		sort.Slice(roots, func(i, j int) bool {
+			return roots[i].Pos() < roots[j].Pos()
+		})
+		graph = rta.Analyze(roots, true).CallGraph
+	default:
+		return nil, fmt.Errorf("unknown call graph algorithm %q", algorithm)
+	}
+	graph.DeleteSyntheticNodes()
+
+	type edgeKey struct {
+		caller, callee string
+		pos            Position
+	}
+	seen := make(map[edgeKey]bool)
+	edges := make([]CallEdge, 0)
+	callgraph.GraphVisitEdges(graph, func(e *callgraph.Edge) error {
+		caller, callee := declaredFunc(e.Caller.Func), declaredFunc(e.Callee.Func)
+		if caller == nil || callee == nil || e.Site == nil {
+			return nil
+		}
+		if withSyntax[caller.Pkg()] == nil || withSyntax[callee.Pkg()] == nil {
+			return nil
+		}
+		// Calls of function literals are followed into them instead.
+		if e.Callee.Func.Parent() != nil {
+			return nil
+		}
+
+		edge := CallEdge{
+			Caller:   functionID(caller),
+			Callee:   functionID(callee),
+			Package:  caller.Pkg().Path(),
+			Position: newPosition(fset, e.Site.Pos(), token.NoPos),
+			Dynamic:  e.Site.Common().StaticCallee() == nil,
+		}
+		key := edgeKey{edge.Caller, edge.Callee, edge.Position}
+		if !seen[key] {
+			seen[key] = true
+			edges = append(edges, edge)
+		}
+		return nil
+	})
+
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.Caller != b.Caller {
+			return a.Caller < b.Caller
+		}
+		if a.Position != b.Position {
+			if a.Position.Path != b.Position.Path {
+				return a.Position.Path < b.Position.Path
+			}
+			return a.Position.Line < b.Position.Line
+		}
+		return a.Callee < b.Callee
+	})
+	return edges, nil
+}
+
+// declaredFunc returns the declared function or method fn belongs to: the
+// generic one for instantiations, and the enclosing one for function
+// literals. It returns nil for synthetic functions, such as package
+// initializers.
+func declaredFunc(fn *ssa.Function) *types.Func {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	obj, _ := fn.Object().(*types.Func)
+	return obj
+}
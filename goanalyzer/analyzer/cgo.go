@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// usesCgo reports whether any file of pkg imports "C", including files that
+// were left out of the build because cgo is disabled.
+func usesCgo(pkg *packages.Package) bool {
+	// cgo rewrites the files it processes, so the compiled files no longer
+	// match the source files.
+	goFiles := make(map[string]bool, len(pkg.GoFiles))
+	for _, filename := range pkg.GoFiles {
+		goFiles[filename] = true
+	}
+	for _, filename := range pkg.CompiledGoFiles {
+		if !goFiles[filename] {
+			return true
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, imp := range file.Imports {
+			if path, err := strconv.Unquote(imp.Path.Value); err == nil && path == "C" {
+				return true
+			}
+		}
+	}
+
+	fset := token.NewFileSet()
+	for _, filename := range pkg.IgnoredFiles {
+		if !strings.HasSuffix(filename, ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filename, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if path, err := strconv.Unquote(imp.Path.Value); err == nil && path == "C" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCgoDeclaration reports whether name is one of the pseudo-declarations
+// generated by cgo for the C side of a package.
+func isCgoDeclaration(name string) bool {
+	return strings.HasPrefix(name, "_C") || strings.HasPrefix(name, "_cgo_")
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// codeGenerator renders the types of analyzed declarations as Go source
+// of another package, keeping track of the imports they need.
+type codeGenerator struct {
+	// imports holds the alias of each imported path, and aliases the
+	// aliases taken.
+	imports map[string]string
+	aliases map[string]bool
+}
+
+func newCodeGenerator() *codeGenerator {
+	return &codeGenerator{imports: make(map[string]string), aliases: make(map[string]bool)}
+}
+
+// source returns the formatted source of a file of package pkgName,
+// starting with the comment header, with the imports needed and body.
+func (g *codeGenerator) source(header, pkgName string, body []byte) ([]byte, error) {
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// %s\n\npackage %s\n\n", header, pkgName)
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		file.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&file, "%s %s\n", g.imports[path], strconv.Quote(path))
+		}
+		file.WriteString(")\n\n")
+	}
+	file.Write(body)
+	return format.Source(file.Bytes())
+}
+
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// qualified returns name qualified by the package at path, importing it.
+// Every import is aliased, as package names cannot be told from import
+// paths alone.
+func (g *codeGenerator) qualified(path, name string) string {
+	if path == "" {
+		return name
+	}
+	if alias, ok := g.imports[path]; ok {
+		return alias + "." + name
+	}
+	elems := strings.Split(path, "/")
+	base := elems[len(elems)-1]
+	if versionSuffix.MatchString(base) && len(elems) > 1 {
+		base = elems[len(elems)-2]
+	}
+	base = strings.Map(func(r rune) rune {
+		if r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, base)
+	if !token.IsIdentifier(base) {
+		base = "pkg_" + base
+	}
+	alias := base
+	for i := 2; g.aliases[alias]; i++ {
+		alias = base + strconv.Itoa(i)
+	}
+	g.importAs(path, alias)
+	return alias + "." + name
+}
+
+func (g *codeGenerator) importAs(path, alias string) {
+	g.imports[path] = alias
+	g.aliases[alias] = true
+}
+
+// topLevelType renders ref, a parameter or result type written as typ in
+// the analysis report. TypeRefs only give the kind of anonymous interfaces
+// and structs, so typ tells whether they are empty.
+func (g *codeGenerator) topLevelType(ref *TypeRef, typ string) (string, error) {
+	rest := strings.NewReplacer("interface{}", "", "struct{}", "").Replace(typ)
+	if strings.Contains(rest, "interface{") || strings.Contains(rest, "struct{") {
+		return "", fmt.Errorf("type %s has an anonymous interface or struct type with members, which is not supported", typ)
+	}
+	return g.typ(ref)
+}
+
+// typ renders ref as Go source.
+func (g *codeGenerator) typ(ref *TypeRef) (string, error) {
+	elem := func() (string, error) {
+		return g.typ(ref.Elem)
+	}
+	switch ref.Kind {
+	case "basic", "typeParam":
+		return ref.Name, nil
+	case "named":
+		if ref.Package != "" && !token.IsExported(ref.Name) {
+			return "", fmt.Errorf("unexported type %s.%s cannot be named outside its package", ref.Package, ref.Name)
+		}
+		name := g.qualified(ref.Package, ref.Name)
+		if len(ref.TypeArgs) > 0 {
+			args, err := g.types(ref.TypeArgs)
+			if err != nil {
+				return "", err
+			}
+			name += "[" + strings.Join(args, ", ") + "]"
+		}
+		return name, nil
+	case "pointer":
+		e, err := elem()
+		return "*" + e, err
+	case "slice":
+		e, err := elem()
+		return "[]" + e, err
+	case "array":
+		e, err := elem()
+		return fmt.Sprintf("[%d]%s", ref.Len, e), err
+	case "map":
+		key, err := g.typ(ref.Key)
+		if err != nil {
+			return "", err
+		}
+		e, err := elem()
+		return "map[" + key + "]" + e, err
+	case "chan":
+		e, err := elem()
+		switch ref.Dir {
+		case "send":
+			return "chan<- " + e, err
+		case "recv":
+			return "<-chan " + e, err
+		}
+		return "chan " + e, err
+	case "func":
+		params, err := g.types(ref.Params)
+		if err != nil {
+			return "", err
+		}
+		if ref.Variadic && len(params) > 0 {
+			params[len(params)-1] = "..." + strings.TrimPrefix(params[len(params)-1], "[]")
+		}
+		results, err := g.types(ref.Results)
+		if err != nil {
+			return "", err
+		}
+		fn := "func(" + strings.Join(params, ", ") + ")"
+		switch len(results) {
+		case 0:
+		case 1:
+			fn += " " + results[0]
+		default:
+			fn += " (" + strings.Join(results, ", ") + ")"
+		}
+		return fn, nil
+	case "struct":
+		return "struct{}", nil
+	case "interface":
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported type kind %q", ref.Kind)
+}
+
+func (g *codeGenerator) types(refs []*TypeRef) ([]string, error) {
+	rendered := make([]string, len(refs))
+	for i, ref := range refs {
+		typ, err := g.typ(ref)
+		if err != nil {
+			return nil, err
+		}
+		rendered[i] = typ
+	}
+	return rendered, nil
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"strings"
+)
+
+// fileConstraints returns the build constraints declared in the header of
+// file, preferring //go:build lines over legacy // +build lines.
+func fileConstraints(file *ast.File) []string {
+	var goBuild, plusBuild []string
+	for _, group := range file.Comments {
+		// Build constraints must appear before the package clause.
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) && !constraint.IsPlusBuild(comment.Text) {
+				continue
+			}
+
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+			if strings.HasPrefix(comment.Text, "//go:build") {
+				goBuild = append(goBuild, expr.String())
+			} else {
+				plusBuild = append(plusBuild, expr.String())
+			}
+		}
+	}
+
+	if len(goBuild) > 0 {
+		return goBuild
+	}
+	return plusBuild
+}
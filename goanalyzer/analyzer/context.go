@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findIgnoredContexts returns the methods that take a context.Context
+// parameter but never refer to it, each once however many such parameters
+// it has. The check is best-effort: a context that is only stored or passed
+// along via a closure still counts as used, and parameters named _ or left
+// unnamed are taken as deliberately ignored. A parameter shadowed before
+// any use, as by ctx := context.Background(), is reported, since the new
+// ctx is another variable.
+func findIgnoredContexts(pkg *packages.Package, skip func(types.Object) bool) []Declaration {
+	ignored := make([]Declaration, 0)
+
+	used := make(map[types.Object]bool)
+	for _, obj := range pkg.TypesInfo.Uses {
+		used[obj] = true
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil {
+				continue
+			}
+			method, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func)
+			if !ok || skip(method) {
+				continue
+			}
+
+		params:
+			for _, field := range fn.Type.Params.List {
+				for _, name := range field.Names {
+					param := pkg.TypesInfo.Defs[name]
+					if param == nil || name.Name == "_" || !isContextType(param.Type()) || used[param] {
+						continue
+					}
+					ignored = append(ignored, Declaration{
+						Name:     receiverName(method) + "." + method.Name(),
+						Package:  pkg.PkgPath,
+						Position: newPosition(pkg.Fset, method.Pos(), token.NoPos),
+					})
+					break params
+				}
+			}
+		}
+	}
+
+	return ignored
+}
+
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
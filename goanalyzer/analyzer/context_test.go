@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestFindIgnoredContexts(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/jobs\n\ngo 1.22\n",
+		"jobs.go": `package jobs
+
+import "context"
+
+type Runner struct{}
+
+func (Runner) Used(ctx context.Context) error { return ctx.Err() }
+
+func (Runner) Unused(ctx context.Context) {}
+
+func (Runner) Both(ctx, parent context.Context) {}
+
+func (Runner) Reassigned(ctx context.Context) error {
+	ctx = context.Background()
+	return ctx.Err()
+}
+
+func (Runner) Redeclared(ctx context.Context) error {
+	if ctx := context.Background(); ctx != nil {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (Runner) Blank(_ context.Context) {}
+`,
+	})
+	result, err := New(func(opts *Options) {
+		opts.Sections = map[string]bool{"ignoredContexts": true}
+	}).Analyze(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, decl := range result.IgnoredContexts {
+		got = append(got, decl.Name)
+	}
+	slices.Sort(got)
+	if want := []string{"Runner.Both", "Runner.Redeclared", "Runner.Unused"}; !slices.Equal(got, want) {
+		t.Errorf("ignored contexts = %v, want %v", got, want)
+	}
+}
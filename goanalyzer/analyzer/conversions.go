@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Conversion is an explicit conversion such as Celsius(f).
+type Conversion struct {
+	Package  string   `json:"package"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Position Position `json:"position"`
+}
+
+// findConversions returns the explicit conversions in pkg between distinct
+// named types. With includeBasic, conversions between a named type and a
+// basic one, as in float64(c) or Celsius(100), are reported too.
+func findConversions(pkg *packages.Package, includeBasic bool, style typeStyle, skipFile func(filename string) bool) []Conversion {
+	conversions := make([]Conversion, 0)
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			if tv, ok := pkg.TypesInfo.Types[call.Fun]; !ok || !tv.IsType() {
+				return true
+			}
+
+			from, to := pkg.TypesInfo.TypeOf(call.Args[0]), pkg.TypesInfo.TypeOf(call)
+			if from == nil || to == nil || types.Identical(from, to) {
+				return true
+			}
+			_, fromNamed := from.(*types.Named)
+			_, toNamed := to.(*types.Named)
+			_, fromBasic := from.(*types.Basic)
+			_, toBasic := to.(*types.Basic)
+			switch {
+			case fromNamed && toNamed:
+			case includeBasic && (fromNamed && toBasic || fromBasic && toNamed):
+			default:
+				return true
+			}
+
+			conversions = append(conversions, Conversion{
+				Package:  pkg.PkgPath,
+				From:     style.typeString(from, pkg.Types),
+				To:       style.typeString(to, pkg.Types),
+				Position: newPosition(pkg.Fset, call.Pos(), call.End()),
+			})
+			return true
+		})
+	}
+	return conversions
+}
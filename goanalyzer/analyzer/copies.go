@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ValueCopyWarning is a call passing a struct by value to a parameter of
+// interface type.
+type ValueCopyWarning struct {
+	Package   string   `json:"package"`
+	Type      string   `json:"type"`
+	Interface string   `json:"interface"`
+	Reason    string   `json:"reason"`
+	Position  Position `json:"position"`
+}
+
+// findValueCopies flags arguments that copy a struct into a non-empty
+// interface when the struct is larger than threshold bytes, or when it has
+// pointer-receiver methods, which suggests it is meant to be shared and the
+// callee would be working on a copy. This is a heuristic: small or
+// immutable structs are commonly passed by value on purpose, and
+// assignments and returns are not checked. Empty interfaces are skipped, as
+// passing values to fmt-style functions is routine.
+func findValueCopies(pkg *packages.Package, sizes types.Sizes, threshold int64, style typeStyle, skipFile func(filename string) bool) []ValueCopyWarning {
+	warnings := make([]ValueCopyWarning, 0)
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if tv, ok := pkg.TypesInfo.Types[call.Fun]; !ok || tv.IsType() {
+				return true
+			}
+			signature, ok := pkg.TypesInfo.TypeOf(call.Fun).Underlying().(*types.Signature)
+			if !ok {
+				return true
+			}
+
+			for i, arg := range call.Args {
+				param := parameterType(signature, i, call.Ellipsis.IsValid())
+				iface, ok := param.Underlying().(*types.Interface)
+				if !ok || iface.NumMethods() == 0 {
+					continue
+				}
+				argType := pkg.TypesInfo.TypeOf(arg)
+				if argType == nil {
+					continue
+				}
+				if _, ok := argType.Underlying().(*types.Struct); !ok {
+					continue
+				}
+
+				var reason string
+				if size := sizes.Sizeof(argType); size > threshold {
+					reason = fmt.Sprintf("copies %d bytes", size)
+				} else if types.NewMethodSet(types.NewPointer(argType)).Len() > types.NewMethodSet(argType).Len() {
+					reason = "has pointer-receiver methods, so the interface holds a copy of a value meant to be shared"
+				} else {
+					continue
+				}
+
+				warnings = append(warnings, ValueCopyWarning{
+					Package:   pkg.PkgPath,
+					Type:      style.typeString(argType, pkg.Types),
+					Interface: style.typeString(param, pkg.Types),
+					Reason:    reason,
+					Position:  newPosition(pkg.Fset, arg.Pos(), arg.End()),
+				})
+			}
+			return true
+		})
+	}
+	return warnings
+}
+
+// parameterType returns the type the i'th argument of a call is assigned
+// to, unpacking a variadic parameter unless the call spreads a slice.
+func parameterType(signature *types.Signature, i int, ellipsis bool) types.Type {
+	params := signature.Params()
+	last := params.Len() - 1
+	if i < last || !signature.Variadic() {
+		if i > last {
+			return types.Typ[types.Invalid]
+		}
+		return params.At(i).Type()
+	}
+	t := params.At(last).Type()
+	if !ellipsis {
+		if slice, ok := t.(*types.Slice); ok {
+			return slice.Elem()
+		}
+	}
+	return t
+}
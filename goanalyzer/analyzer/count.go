@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"io"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// CountReport is the output of -count-only.
+type CountReport struct {
+	SchemaVersion    string   `json:"schemaVersion"`
+	GeneratorVersion string   `json:"generatorVersion"`
+	Interfaces       int      `json:"interfaces"`
+	Structs          int      `json:"structs"`
+	Errors           []string `json:"errors"`
+}
+
+// countLoadMode leaves out NeedTypesInfo: counting declarations needs the
+// package scopes, and the syntax only to spot generated files, but none of
+// the per-expression type information. Export data alone is not enough, as
+// it omits unexported types the exported API does not mention.
+const countLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedImports | packages.NeedTypes | packages.NeedSyntax | packages.NeedModule
+
+// WriteCounts writes the CountReport for result to w as JSON.
+func WriteCounts(w io.Writer, result *Result) error {
+	return writeJSON(w, CountReport{
+		SchemaVersion:    result.SchemaVersion,
+		GeneratorVersion: result.GeneratorVersion,
+		Interfaces:       len(result.Interfaces),
+		Structs:          len(result.Structs),
+		Errors:           result.Errors,
+	})
+}
@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Cycle is an import cycle between packages, or a cycle of struct types
+// embedding each other through pointers, as Kind says. Path is the shortest
+// cycle through the strongly connected packages or types, by import path or
+// type ID, and ends where it starts. Package is the package of the first,
+// and Position its import of the second, or its declaration.
+type Cycle struct {
+	Kind     string   `json:"kind"`
+	Path     []string `json:"path"`
+	Package  string   `json:"package"`
+	Position Position `json:"position"`
+}
+
+// findImportCycles reports one cycle for each set of packages in nodes
+// importing each other, positioned by the syntax of pkgs.
+func findImportCycles(nodes []PackageImports, pkgs []*packages.Package) []Cycle {
+	edges := make(map[string][]string, len(nodes))
+	for _, node := range nodes {
+		edges[node.Path] = node.Imports
+	}
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		if !isTestVariant(pkg) {
+			byPath[pkg.PkgPath] = pkg
+		}
+	}
+
+	cycles := make([]Cycle, 0)
+	for _, members := range components(importCycles(nodes)) {
+		path := shortestCycle(members, edges)
+		cycle := Cycle{Kind: "import", Path: path, Package: path[0]}
+		if pkg := byPath[path[0]]; pkg != nil {
+			cycle.Position = importPosition(pkg, path[1])
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// importPosition returns the position of the first import of path in pkg.
+func importPosition(pkg *packages.Package, path string) Position {
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			if imported, err := strconv.Unquote(spec.Path.Value); err == nil && imported == path {
+				return newPosition(pkg.Fset, spec.Pos(), spec.End())
+			}
+		}
+	}
+	return Position{}
+}
+
+// findTypeCycles reports one cycle for each set of struct types of pkg
+// embedding each other. Embedding by value cannot be cyclic, and types of
+// different packages cannot embed each other both ways without an import
+// cycle, so only pointers within pkg need following.
+func findTypeCycles(pkg *packages.Package, skip func(types.Object) bool) []Cycle {
+	edges := make(map[string][]string)
+	positions := make(map[string]Position)
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() || skip(obj) {
+			continue
+		}
+		strct, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		id := typeID(obj)
+		edges[id] = make([]string, 0)
+		positions[id] = newPosition(pkg.Fset, obj.Pos(), token.NoPos)
+		for i := 0; i < strct.NumFields(); i++ {
+			field := strct.Field(i)
+			if !field.Anonymous() {
+				continue
+			}
+			t := field.Type()
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			if named, ok := t.(*types.Named); ok && named.Obj().Pkg() == pkg.Types {
+				edges[id] = append(edges[id], typeID(named.Origin().Obj()))
+			}
+		}
+	}
+
+	nodes := make([]string, 0, len(edges))
+	for id := range edges {
+		nodes = append(nodes, id)
+	}
+	sort.Strings(nodes)
+	cycles := make([]Cycle, 0)
+	for _, members := range components(stronglyConnected(nodes, edges)) {
+		path := shortestCycle(members, edges)
+		cycles = append(cycles, Cycle{Kind: "type", Path: path, Package: pkg.PkgPath, Position: positions[path[0]]})
+	}
+	return cycles
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// of nodes and edges, by Tarjan's algorithm, as a map from node to
+// component index. Nodes in no cycle are left out.
+func stronglyConnected(nodes []string, edges map[string][]string) map[string]int {
+	var (
+		index   = make(map[string]int)
+		low     = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		next    int
+		cycles  = make(map[string]int)
+		nCycles int
+		connect func(node string)
+	)
+	connect = func(node string) {
+		index[node], low[node] = next, next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfEdge := false
+		for _, succ := range edges[node] {
+			if succ == node {
+				selfEdge = true
+			}
+			if _, visited := index[succ]; !visited {
+				connect(succ)
+				low[node] = min(low[node], low[succ])
+			} else if onStack[succ] {
+				low[node] = min(low[node], index[succ])
+			}
+		}
+
+		if low[node] != index[node] {
+			return
+		}
+		i := len(stack) - 1
+		for stack[i] != node {
+			i--
+		}
+		component := stack[i:]
+		stack = stack[:i]
+		for _, member := range component {
+			onStack[member] = false
+		}
+		if len(component) > 1 || selfEdge {
+			for _, member := range component {
+				cycles[member] = nCycles
+			}
+			nCycles++
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+	return cycles
+}
+
+// components groups the nodes of the components stronglyConnected found,
+// each sorted, in the order of their first node.
+func components(component map[string]int) [][]string {
+	byIndex := make(map[int][]string)
+	for node, i := range component {
+		byIndex[i] = append(byIndex[i], node)
+	}
+	groups := make([][]string, 0, len(byIndex))
+	for _, members := range byIndex {
+		sort.Strings(members)
+		groups = append(groups, members)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+	return groups
+}
+
+// shortestCycle returns the shortest path along edges from a node of
+// members back to itself, staying within members, which must be strongly
+// connected. Ties go to the path starting at the first node of members.
+func shortestCycle(members []string, edges map[string][]string) []string {
+	within := make(map[string]bool, len(members))
+	for _, member := range members {
+		within[member] = true
+	}
+
+	var best []string
+	for _, start := range members {
+		// Breadth-first search, remembering how each node was reached.
+		from := map[string]string{}
+		queue := []string{start}
+		found := false
+		for len(queue) > 0 && !found {
+			node := queue[0]
+			queue = queue[1:]
+			for _, next := range edges[node] {
+				if !within[next] {
+					continue
+				}
+				if next == start {
+					from[start] = node
+					found = true
+					break
+				}
+				if _, seen := from[next]; !seen {
+					from[next] = node
+					queue = append(queue, next)
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+
+		path := []string{start}
+		for node := from[start]; node != start; node = from[node] {
+			path = append(path, node)
+		}
+		path = append(path, start)
+		// The path was collected backwards.
+		for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+			path[i], path[j] = path[j], path[i]
+		}
+		if best == nil || len(path) < len(best) {
+			best = path
+		}
+	}
+	return best
+}
+
+// cycleKey orders cycles by kind, then path.
+func cycleKey(cycle Cycle) string {
+	return cycle.Kind + " " + strings.Join(cycle.Path, " ")
+}
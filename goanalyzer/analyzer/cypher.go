@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeCypher emits Neo4j Cypher statements for the types in result. Node
+// ids are the IDs of the declarations, so that they survive moves between
+// files, and every statement uses MERGE so that loading the same output
+// twice does not duplicate the graph.
+func writeCypher(w io.Writer, result AnalysisResult) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// schemaVersion: %s, generatorVersion: %s\n", result.SchemaVersion, result.GeneratorVersion)
+
+	// Implemented interfaces are declarations, found by package and name.
+	ifaceIDs := make(map[[2]string]string)
+	for _, iface := range result.Interfaces {
+		ifaceIDs[[2]string{iface.Package, iface.Name}] = iface.ID
+	}
+
+	seenPackages := make(map[string]bool)
+	writeType := func(label, id, name, pkg string, pos Position) {
+		if !seenPackages[pkg] {
+			seenPackages[pkg] = true
+			fmt.Fprintf(bw, "MERGE (p:Package {id: %s}) SET p.path = %s;\n", cypherString(pkg), cypherString(pkg))
+		}
+		fmt.Fprintf(bw, "MERGE (t:Type {id: %s}) SET t:%s, t.name = %s, t.path = %s, t.line = %d;\n",
+			cypherString(id), label, cypherString(name), cypherString(pos.Path), pos.Line)
+		fmt.Fprintf(bw, "MATCH (t:Type {id: %s}), (p:Package {id: %s}) MERGE (t)-[:IN_PACKAGE]->(p);\n",
+			cypherString(id), cypherString(pkg))
+	}
+	writeMethods := func(typeID string, methods []MethodInfo) {
+		for _, method := range methods {
+			fmt.Fprintf(bw, "MERGE (m:Method {id: %s}) SET m.name = %s, m.path = %s, m.line = %d;\n",
+				cypherString(method.ID), cypherString(method.Name), cypherString(method.Position.Path), method.Position.Line)
+			fmt.Fprintf(bw, "MATCH (t:Type {id: %s}), (m:Method {id: %s}) MERGE (t)-[:HAS_METHOD]->(m);\n",
+				cypherString(typeID), cypherString(method.ID))
+		}
+	}
+
+	for _, iface := range result.Interfaces {
+		writeType("Interface", iface.ID, iface.Name, iface.Package, iface.Position)
+		writeMethods(iface.ID, iface.Methods)
+	}
+
+	for _, strct := range result.Structs {
+		writeType("Struct", strct.ID, strct.Name, strct.Package, strct.Position)
+		writeMethods(strct.ID, strct.Methods)
+
+		for _, iface := range strct.ImplementedInterfaces {
+			ifaceID, ok := ifaceIDs[[2]string{iface.Package, iface.Name}]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(bw, "MATCH (s:Type {id: %s}), (i:Type {id: %s}) MERGE (s)-[:IMPLEMENTS]->(i);\n",
+				cypherString(strct.ID), cypherString(ifaceID))
+		}
+		// Embedded types may live outside the analyzed packages, so their
+		// nodes are created on demand.
+		for _, embedded := range strct.Embedded {
+			if embedded.ID == "" {
+				continue
+			}
+			fmt.Fprintf(bw, "MATCH (s:Type {id: %s}) MERGE (e:Type {id: %s}) MERGE (s)-[:EMBEDS]->(e);\n",
+				cypherString(strct.ID), cypherString(embedded.ID))
+		}
+	}
+
+	return bw.Flush()
+}
+
+func cypherString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
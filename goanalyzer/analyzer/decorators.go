@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// Decorator is a struct wrapping a value of an interface it implements
+// itself, as adapters and decorators do:
+//
+//	type loggingStore struct {
+//		next Store
+//	}
+//
+//	func (s *loggingStore) Get(key string) (string, error) { ... }
+type Decorator struct {
+	Struct    Declaration `json:"struct"`
+	Interface Declaration `json:"interface"`
+	Field     string      `json:"field"`
+	// Embedded is set when the interface is embedded, so that methods the
+	// struct does not declare are forwarded implicitly.
+	Embedded bool `json:"embedded,omitempty"`
+}
+
+// findDecorators returns a Decorator for every field of the struct obj
+// names whose type is a named interface the struct, or a pointer to it,
+// implements. Interfaces from any package count.
+func findDecorators(obj *types.TypeName, strct *types.Struct, fset *token.FileSet, cache *typeCache) []Decorator {
+	var decorators []Decorator
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		named, ok := field.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		iface, ok := named.Underlying().(*types.Interface)
+		if !ok || iface.Empty() {
+			continue
+		}
+		if !cache.implements(obj.Type(), iface) {
+			continue
+		}
+
+		decorators = append(decorators, Decorator{
+			Struct:    objectDeclaration(obj, fset),
+			Interface: objectDeclaration(named.Origin().Obj(), fset),
+			Field:     field.Name(),
+			Embedded:  field.Anonymous(),
+		})
+	}
+	return decorators
+}
+
+// objectDeclaration describes a package-level object.
+func objectDeclaration(obj types.Object, fset *token.FileSet) Declaration {
+	decl := Declaration{Name: obj.Name()}
+	if obj.Pkg() != nil {
+		decl.Package = obj.Pkg().Path()
+	}
+	decl.Position = newPosition(fset, obj.Pos(), token.NoPos)
+	return decl
+}
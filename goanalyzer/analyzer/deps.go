@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// collectMethodDecls indexes the method declarations in pkg by the type name
+// of their receiver.
+func collectMethodDecls(pkg *packages.Package) map[*types.TypeName][]*ast.FuncDecl {
+	decls := make(map[*types.TypeName][]*ast.FuncDecl)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Body == nil {
+				continue
+			}
+
+			fn, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			recv := fn.Type().(*types.Signature).Recv()
+			if recv == nil {
+				continue
+			}
+
+			recvType := recv.Type()
+			if ptr, ok := recvType.(*types.Pointer); ok {
+				recvType = ptr.Elem()
+			}
+			if named, ok := recvType.(*types.Named); ok {
+				typeName := named.Origin().Obj()
+				decls[typeName] = append(decls[typeName], funcDecl)
+			}
+		}
+	}
+	return decls
+}
+
+// methodDependencies returns the sorted import paths of the packages whose
+// objects are referenced from the given method bodies, excluding pkg itself.
+func methodDependencies(decls []*ast.FuncDecl, pkg *packages.Package) []string {
+	seen := make(map[string]bool)
+	for _, decl := range decls {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			obj := pkg.TypesInfo.Uses[ident]
+			if obj == nil || obj.Pkg() == nil || obj.Pkg() == pkg.Types {
+				return true
+			}
+
+			seen[obj.Pkg().Path()] = true
+			return true
+		})
+	}
+
+	deps := make([]string, 0, len(seen))
+	for path := range seen {
+		deps = append(deps, path)
+	}
+	sort.Strings(deps)
+	return deps
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"go/types"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// PackageImports is a node of the package import graph: a package of the
+// main module and the packages of the main module it imports. The packages
+// it imports from elsewhere, including the standard library, are listed
+// apart, and are not nodes of the graph.
+type PackageImports struct {
+	Path            string   `json:"path"`
+	Imports         []string `json:"imports"`
+	ExternalImports []string `json:"externalImports"`
+	// Declarations counts the package-level declarations.
+	Declarations int `json:"declarations"`
+}
+
+// collectPackageImports returns the import graph node for pkg. Imports are
+// split into those among the analyzed packages and the external ones by
+// finishPackageImports, once all of them are known.
+//
+// go/packages drops the import closing a cycle from pkg.Imports, so the
+// import declarations of the syntax trees are read as well.
+func collectPackageImports(pkg *packages.Package, skip func(types.Object) bool) PackageImports {
+	seen := make(map[string]bool)
+	for path := range pkg.Imports {
+		seen[path] = true
+	}
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+				seen[path] = true
+			}
+		}
+	}
+
+	node := PackageImports{Path: pkg.PkgPath, Imports: sortedKeys(seen)}
+	if pkg.Types != nil {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			if !skip(scope.Lookup(name)) {
+				node.Declarations++
+			}
+		}
+	}
+	return node
+}
+
+// finishPackageImports moves the edges to packages outside the graph to
+// the external imports and orders the nodes by path.
+func finishPackageImports(nodes []PackageImports) {
+	known := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		known[node.Path] = true
+	}
+	for i := range nodes {
+		imports := make([]string, 0, len(nodes[i].Imports))
+		external := make([]string, 0)
+		for _, path := range nodes[i].Imports {
+			if known[path] {
+				imports = append(imports, path)
+			} else {
+				external = append(external, path)
+			}
+		}
+		nodes[i].Imports = imports
+		nodes[i].ExternalImports = external
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Path < nodes[j].Path
+	})
+}
+
+// importCycles returns the strongly connected components of the import
+// graph as a map from package path to component index. Packages in no cycle
+// are left out.
+func importCycles(nodes []PackageImports) map[string]int {
+	paths := make([]string, len(nodes))
+	imports := make(map[string][]string, len(nodes))
+	for i, node := range nodes {
+		paths[i] = node.Path
+		imports[node.Path] = node.Imports
+	}
+	return stronglyConnected(paths, imports)
+}
+
+// dotFillColors shade nodes from light to dark by declaration count.
+var dotFillColors = []string{"#deebf7", "#c6dbef", "#9ecae1", "#6baed6", "#4292c6"}
+
+// writeDepsDot emits the package import graph as a Graphviz digraph. Node
+// size and shade grow with the number of declarations, and the edges of
+// import cycles are drawn in red.
+func writeDepsDot(w io.Writer, result AnalysisResult) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// schemaVersion: %s, generatorVersion: %s\n", result.SchemaVersion, result.GeneratorVersion)
+	fmt.Fprintln(bw, "digraph imports {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=box, style=filled, fontname=\"Helvetica\"];")
+
+	most := 0
+	for _, node := range result.PackageImports {
+		most = max(most, node.Declarations)
+	}
+	for _, node := range result.PackageImports {
+		shade := 0
+		if most > 0 {
+			shade = node.Declarations * (len(dotFillColors) - 1) / most
+		}
+		width := 0.75 + math.Sqrt(float64(node.Declarations))*0.25
+		fmt.Fprintf(bw, "\t%s [label=%s, width=%.2f, fillcolor=%s];\n",
+			dotString(node.Path), dotString(fmt.Sprintf("%s\n%d declarations", node.Path, node.Declarations)),
+			width, dotString(dotFillColors[shade]))
+	}
+
+	cycles := importCycles(result.PackageImports)
+	for _, node := range result.PackageImports {
+		for _, imported := range node.Imports {
+			from, inCycle := cycles[node.Path]
+			if to, ok := cycles[imported]; inCycle && ok && from == to {
+				fmt.Fprintf(bw, "\t%s -> %s [color=red, penwidth=2];\n", dotString(node.Path), dotString(imported))
+				continue
+			}
+			fmt.Fprintf(bw, "\t%s -> %s;\n", dotString(node.Path), dotString(imported))
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
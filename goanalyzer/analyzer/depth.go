@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// patternsWithinDepth lists the packages matched by "./..." in cfg.Dir whose
+// directory is at most maxDepth levels below it, 0 being cfg.Dir itself.
+// Only names and files are loaded to find them, so that the packages left
+// out are never type-checked, except as dependencies of the ones kept.
+func patternsWithinDepth(cfg *packages.Config, maxDepth int) ([]string, error) {
+	resolve := *cfg
+	resolve.Mode = packages.NeedName | packages.NeedFiles
+	resolve.Tests = false
+	pkgs, err := packages.Load(&resolve, "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	root := cfg.Dir
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	patterns := make([]string, 0)
+	for _, pkg := range pkgs {
+		files := append(append(pkg.GoFiles, pkg.OtherFiles...), pkg.IgnoredFiles...)
+		if len(files) == 0 {
+			continue
+		}
+		dir := filepath.Dir(files[0])
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = resolved
+		}
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if directoryDepth(rel) <= maxDepth {
+			patterns = append(patterns, pkg.PkgPath)
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no packages within -max-depth %d", maxDepth)
+	}
+	return patterns, nil
+}
+
+// directoryDepth returns the number of directories in the relative path rel.
+func directoryDepth(rel string) int {
+	if rel == "." {
+		return 0
+	}
+	return len(strings.Split(filepath.ToSlash(rel), "/"))
+}
@@ -0,0 +1,291 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ResultDiff is what changed from one result to another. Declarations are
+// matched by ID, and methods and functions by their ID without parameter
+// types, so that a changed signature is reported as such rather than as a
+// removal and an addition.
+type ResultDiff struct {
+	Added   []DiffEntry       `json:"added"`
+	Removed []DiffEntry       `json:"removed"`
+	Changed []SignatureChange `json:"changed"`
+	// BrokenImplementations lists the structs that no longer implement an
+	// interface they did, while both still exist.
+	BrokenImplementations []Implementation `json:"brokenImplementations"`
+	NewImplementations    []Implementation `json:"newImplementations"`
+}
+
+// DiffEntry is an interface, struct, function or method, as Kind says.
+type DiffEntry struct {
+	Kind      string   `json:"kind"`
+	ID        string   `json:"id"`
+	Signature string   `json:"signature,omitempty"`
+	Position  Position `json:"position"`
+}
+
+// SignatureChange is a method or function whose parameter or result types
+// changed. Signatures read "Name(T1,T2)(R1,R2)" as in fingerprints.
+type SignatureChange struct {
+	Kind     string   `json:"kind"`
+	ID       string   `json:"id"`
+	Old      string   `json:"old"`
+	New      string   `json:"new"`
+	Position Position `json:"position"`
+}
+
+type Implementation struct {
+	Struct    Declaration `json:"struct"`
+	Interface Declaration `json:"interface"`
+}
+
+// Empty reports whether nothing changed.
+func (d *ResultDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 &&
+		len(d.BrokenImplementations) == 0 && len(d.NewImplementations) == 0
+}
+
+// Breaking reports whether the changes can break code using the old result:
+// declarations were removed, signatures changed or structs stopped
+// implementing interfaces.
+func (d *ResultDiff) Breaking() bool {
+	return len(d.Removed) > 0 || len(d.Changed) > 0 || len(d.BrokenImplementations) > 0
+}
+
+// diffMember is a method or function as matched across results.
+type diffMember struct {
+	kind      string
+	id        string
+	signature string
+	position  Position
+}
+
+// Diff compares two results, which must carry declaration IDs. Signatures
+// are compared with types qualified by import path, so results written in
+// different type styles compare equal.
+func Diff(before, after *Result) (*ResultDiff, error) {
+	for _, r := range []struct {
+		name   string
+		result *Result
+	}{{"old", before}, {"new", after}} {
+		if !hasIDs(r.result) {
+			return nil, fmt.Errorf("%s result (schemaVersion %s) has no declaration IDs; generate it again", r.name, r.result.SchemaVersion)
+		}
+	}
+
+	diff := &ResultDiff{
+		Added:                 make([]DiffEntry, 0),
+		Removed:               make([]DiffEntry, 0),
+		Changed:               make([]SignatureChange, 0),
+		BrokenImplementations: make([]Implementation, 0),
+		NewImplementations:    make([]Implementation, 0),
+	}
+
+	oldTypes, newTypes := diffTypes(before), diffTypes(after)
+	for id, entry := range oldTypes {
+		if _, ok := newTypes[id]; !ok {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+	for id, entry := range newTypes {
+		if _, ok := oldTypes[id]; !ok {
+			diff.Added = append(diff.Added, entry)
+		}
+	}
+
+	oldMembers, newMembers := diffMembers(before), diffMembers(after)
+	for key, was := range oldMembers {
+		is, ok := newMembers[key]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, DiffEntry{Kind: was.kind, ID: was.id, Signature: was.signature, Position: was.position})
+		case is.signature != was.signature:
+			diff.Changed = append(diff.Changed, SignatureChange{Kind: is.kind, ID: key, Old: was.signature, New: is.signature, Position: is.position})
+		}
+	}
+	for key, is := range newMembers {
+		if _, ok := oldMembers[key]; !ok {
+			diff.Added = append(diff.Added, DiffEntry{Kind: is.kind, ID: is.id, Signature: is.signature, Position: is.position})
+		}
+	}
+
+	// Interfaces from outside the results, such as io.Reader, are taken to
+	// still exist.
+	exists := func(id string) bool {
+		_, ok := newTypes[id]
+		return ok
+	}
+	oldImpls, newImpls := implementations(before), implementations(after)
+	for key, impl := range oldImpls {
+		_, declared := oldTypes[key[1]]
+		if _, ok := newImpls[key]; !ok && exists(key[0]) && (!declared || exists(key[1])) {
+			diff.BrokenImplementations = append(diff.BrokenImplementations, impl)
+		}
+	}
+	for key, impl := range newImpls {
+		if _, ok := oldImpls[key]; !ok {
+			diff.NewImplementations = append(diff.NewImplementations, impl)
+		}
+	}
+
+	sortDiffEntries(diff.Added)
+	sortDiffEntries(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].ID < diff.Changed[j].ID
+	})
+	sortImplementations(diff.BrokenImplementations)
+	sortImplementations(diff.NewImplementations)
+	return diff, nil
+}
+
+// hasIDs reports whether result comes from a version of the analyzer that
+// identifies declarations.
+func hasIDs(result *Result) bool {
+	if len(result.Interfaces) > 0 {
+		return result.Interfaces[0].ID != ""
+	}
+	if len(result.Structs) > 0 {
+		return result.Structs[0].ID != ""
+	}
+	return true
+}
+
+// diffTypes returns the interfaces and structs of result by ID.
+func diffTypes(result *Result) map[string]DiffEntry {
+	found := make(map[string]DiffEntry)
+	for _, iface := range result.Interfaces {
+		found[iface.ID] = DiffEntry{Kind: "interface", ID: iface.ID, Position: iface.Position}
+	}
+	for _, strct := range result.Structs {
+		found[strct.ID] = DiffEntry{Kind: "struct", ID: strct.ID, Position: strct.Position}
+	}
+	return found
+}
+
+// diffMembers returns the methods and functions of result by their ID
+// without parameter types.
+func diffMembers(result *Result) map[string]diffMember {
+	found := make(map[string]diffMember)
+	addMethods := func(owner string, methods []MethodInfo) {
+		for _, method := range methods {
+			found[owner+"."+method.Name] = diffMember{
+				kind:      "method",
+				id:        method.ID,
+				signature: canonicalSignature(method.Name, method.Signature, method.Parameters, method.Variadic, method.ReturnTypes),
+				position:  method.Position,
+			}
+		}
+	}
+	for _, iface := range result.Interfaces {
+		addMethods(iface.ID, iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		addMethods(strct.ID, strct.Methods)
+	}
+	for _, fn := range result.Functions {
+		found[fn.Package+"."+fn.Name] = diffMember{
+			kind:      "function",
+			id:        fn.ID,
+			signature: canonicalSignature(fn.Name, fn.Signature, fn.Parameters, fn.Variadic, fn.ReturnTypes),
+			position:  fn.Position,
+		}
+	}
+	return found
+}
+
+// implementations returns the implements relations of result, keyed by the
+// IDs of the struct and the interface.
+func implementations(result *Result) map[[2]string]Implementation {
+	found := make(map[[2]string]Implementation)
+	for _, strct := range result.Structs {
+		for _, iface := range strct.ImplementedInterfaces {
+			key := [2]string{strct.ID, iface.Package + "." + iface.Name}
+			found[key] = Implementation{
+				Struct:    Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position},
+				Interface: iface,
+			}
+		}
+	}
+	return found
+}
+
+func sortDiffEntries(entries []DiffEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+func sortImplementations(impls []Implementation) {
+	sort.Slice(impls, func(i, j int) bool {
+		a, b := impls[i], impls[j]
+		if a.Struct.Package+"."+a.Struct.Name != b.Struct.Package+"."+b.Struct.Name {
+			return a.Struct.Package+"."+a.Struct.Name < b.Struct.Package+"."+b.Struct.Name
+		}
+		return a.Interface.Package+"."+a.Interface.Name < b.Interface.Package+"."+b.Interface.Name
+	})
+}
+
+// DiffFormats are the formats WriteDiff supports.
+var DiffFormats = []string{"json", "text"}
+
+// WriteDiff writes diff to w as JSON or as text, one line per change,
+// marked with + for additions, - for removals, ~ for changed signatures, !
+// for broken implementations and * for new ones:
+//
+//	~ method example.com/app/store.Store.Get: Get(string)(error) -> Get(context.Context,string)(error)
+//	! example.com/app/pg.Store no longer implements example.com/app/store.Store
+//	* example.com/app/pg.Store now implements example.com/app/store.Store
+//	+ interface example.com/app/store.Cache @ store/cache.go:12
+//	- method example.com/app/store.Store.Flush() @ store/store.go:20
+func WriteDiff(w io.Writer, diff *ResultDiff, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(w, diff)
+	case "text":
+	default:
+		return fmt.Errorf("unknown diff format %q", format)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, entry := range diff.Added {
+		fmt.Fprintf(bw, "+ %s %s%s\n", entry.Kind, entry.ID, atPosition(entry.Position))
+	}
+	for _, entry := range diff.Removed {
+		fmt.Fprintf(bw, "- %s %s%s\n", entry.Kind, entry.ID, atPosition(entry.Position))
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(bw, "~ %s %s: %s -> %s\n", change.Kind, change.ID, change.Old, change.New)
+	}
+	for _, impl := range diff.BrokenImplementations {
+		fmt.Fprintf(bw, "! %s.%s no longer implements %s.%s\n", impl.Struct.Package, impl.Struct.Name, impl.Interface.Package, impl.Interface.Name)
+	}
+	for _, impl := range diff.NewImplementations {
+		fmt.Fprintf(bw, "* %s.%s now implements %s.%s\n", impl.Struct.Package, impl.Struct.Name, impl.Interface.Package, impl.Interface.Name)
+	}
+	return bw.Flush()
+}
+
+// canonicalSignature renders a method or function as "Name(T1,T2)(R1,R2)",
+// with types qualified by import path whatever the type style. Reports
+// older than the signature field only have the types as rendered, which
+// were qualified by import path by default, and a variadic parameter as
+// its slice type.
+func canonicalSignature(name, signature string, params []ParamInfo, variadic bool, returns []string) string {
+	if signature != "" {
+		return name + signature
+	}
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, param.Type)
+	}
+	if variadic && len(names) > 0 {
+		names[len(names)-1] = "..." + strings.TrimPrefix(names[len(names)-1], "[]")
+	}
+	return name + "(" + strings.Join(names, ",") + ")(" + strings.Join(returns, ",") + ")"
+}
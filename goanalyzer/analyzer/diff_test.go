@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestDiffVariadic(t *testing.T) {
+	const goMod = "module example.com/logs\n\ngo 1.22\n"
+	analyze := func(t *testing.T, src string) *Result {
+		t.Helper()
+		dir := writeModule(t, map[string]string{"go.mod": goMod, "logs.go": "package logs\n\n" + src})
+		result, err := New().Analyze(context.Background(), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	tests := []struct {
+		name          string
+		before, after string
+		changed       []string
+	}{
+		{
+			name:    "function",
+			before:  "func Log(format string, args ...int) {}\n",
+			after:   "func Log(format string, args []int) {}\n",
+			changed: []string{"example.com/logs.Log"},
+		},
+		{
+			name:    "method",
+			before:  "type Logger interface {\n\tLog(format string, args ...int)\n}\n",
+			after:   "type Logger interface {\n\tLog(format string, args []int)\n}\n",
+			changed: []string{"example.com/logs.Logger.Log"},
+		},
+		{
+			name:   "unchanged",
+			before: "func Log(format string, args ...int) {}\n",
+			after:  "func Log(format string, values ...int) {}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Diff(analyze(t, tt.before), analyze(t, tt.after))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var changed []string
+			for _, change := range diff.Changed {
+				changed = append(changed, change.ID)
+			}
+			if !slices.Equal(changed, tt.changed) {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			if got, want := diff.Breaking(), len(tt.changed) > 0; got != want {
+				t.Errorf("Breaking() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// Reports older than the signature field are compared by their rendered
+// parameter types.
+func TestCanonicalSignatureWithoutSignature(t *testing.T) {
+	params := []ParamInfo{{Name: "format", Type: "string"}, {Name: "args", Type: "[]int"}}
+	variadic := canonicalSignature("Log", "", params, true, nil)
+	slice := canonicalSignature("Log", "", params, false, nil)
+	if variadic != "Log(string,...int)()" || slice != "Log(string,[]int)()" {
+		t.Errorf("signatures = %q, %q, want %q, %q", variadic, slice, "Log(string,...int)()", "Log(string,[]int)()")
+	}
+}
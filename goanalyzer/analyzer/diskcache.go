@@ -0,0 +1,318 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// resultCache keeps analysis results on disk between runs. Every package is
+// keyed by a hash of its files, its module's go.mod, the keys of its imports
+// and the options, build configuration and go.work it is analyzed with. A run whose packages all have the
+// keys of an earlier run returns that run's result without type-checking
+// anything. Otherwise all packages are loaded again, but those whose key is
+// unchanged take their packageSections from the cache instead of analyzing
+// them. The sections relating packages to each other, such as implemented
+// interfaces or the interface hierarchy, are always recomputed, as a change
+// in one package can alter them in any other.
+type resultCache struct {
+	dir string
+	// keys maps package IDs to their keys; run combines the keys of the
+	// packages matching the patterns.
+	keys map[string]string
+	run  string
+}
+
+// packageSections are the sections a package contributes on its own, from
+// nothing but its files and the types of its imports.
+type packageSections struct {
+	Functions         []FunctionInfo     `json:"functions,omitempty"`
+	LeakyAPIs         []LeakyAPI         `json:"leakyAPIs,omitempty"`
+	Undocumented      []Declaration      `json:"undocumented,omitempty"`
+	IgnoredContexts   []Declaration      `json:"ignoredContexts,omitempty"`
+	Conversions       []Conversion       `json:"conversions,omitempty"`
+	TypeAssertions    []TypeAssertion    `json:"typeAssertions,omitempty"`
+	EmptyStructUses   []EmptyStructUse   `json:"emptyStructUses,omitempty"`
+	ValueCopyWarnings []ValueCopyWarning `json:"valueCopyWarnings,omitempty"`
+	Enums             []Enum             `json:"enums,omitempty"`
+	API               *APIPackage        `json:"api,omitempty"`
+	LSPSymbols        []LSPDocument      `json:"lspSymbols,omitempty"`
+	Cycles            []Cycle            `json:"cycles,omitempty"`
+}
+
+func (s packageSections) addTo(result *AnalysisResult) {
+	result.Functions = append(result.Functions, s.Functions...)
+	result.LeakyAPIs = append(result.LeakyAPIs, s.LeakyAPIs...)
+	result.Undocumented = append(result.Undocumented, s.Undocumented...)
+	result.IgnoredContexts = append(result.IgnoredContexts, s.IgnoredContexts...)
+	result.Conversions = append(result.Conversions, s.Conversions...)
+	result.TypeAssertions = append(result.TypeAssertions, s.TypeAssertions...)
+	result.EmptyStructUses = append(result.EmptyStructUses, s.EmptyStructUses...)
+	result.ValueCopyWarnings = append(result.ValueCopyWarnings, s.ValueCopyWarnings...)
+	result.Enums = append(result.Enums, s.Enums...)
+	if s.API != nil {
+		result.API = append(result.API, *s.API)
+	}
+	result.LSPSymbols = append(result.LSPSymbols, s.LSPSymbols...)
+	result.Cycles = append(result.Cycles, s.Cycles...)
+}
+
+// DefaultCacheDir returns the directory -cache keeps results in,
+// goanalyzer under the user's cache directory.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goanalyzer"), nil
+}
+
+// openResultCache computes the keys of the packages cfg and patterns load.
+// Only their metadata is loaded for this, which is much cheaper than the
+// syntax and types the analysis needs.
+func openResultCache(dir string, cfg *packages.Config, patterns []string, opts Options) (*resultCache, error) {
+	meta := *cfg
+	meta.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule
+	pkgs, err := packages.Load(&meta, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := cacheFingerprint(cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+	// The workspace chooses the modules, and their versions, of every
+	// package.
+	if work := goWorkFile(cfg); work != "" {
+		fingerprint = fmt.Appendf(fingerprint, "\nwork %s %x", work, fileSum(work, cfg.Overlay))
+	}
+	c := &resultCache{dir: dir, keys: make(map[string]string)}
+	files := make(map[string][]byte)
+	sum := func(filename string) []byte {
+		s, ok := files[filename]
+		if !ok {
+			s = fileSum(filename, cfg.Overlay)
+			files[filename] = s
+		}
+		return s
+	}
+
+	// Packages are hashed with the keys of their imports, so that changing
+	// a type changes the keys of every package depending on it.
+	var key func(pkg *packages.Package) string
+	key = func(pkg *packages.Package) string {
+		if k, ok := c.keys[pkg.ID]; ok {
+			return k
+		}
+		h := sha256.New()
+		h.Write(fingerprint)
+		fmt.Fprintf(h, "\npackage %s %s\n", pkg.ID, pkg.PkgPath)
+		// OtherFiles holds the C sources of cgo packages.
+		for _, filename := range append(append([]string(nil), pkg.GoFiles...), pkg.OtherFiles...) {
+			fmt.Fprintf(h, "file %s %x\n", filename, sum(filename))
+		}
+		// go.mod gives the language version the package is checked with,
+		// and the module information reported.
+		if pkg.Module != nil && pkg.Module.GoMod != "" {
+			fmt.Fprintf(h, "gomod %s %x\n", pkg.Module.GoMod, sum(pkg.Module.GoMod))
+		}
+		paths := make([]string, 0, len(pkg.Imports))
+		for path := range pkg.Imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			fmt.Fprintf(h, "import %s %s\n", path, key(pkg.Imports[path]))
+		}
+		k := hex.EncodeToString(h.Sum(nil))
+		c.keys[pkg.ID] = k
+		return k
+	}
+
+	run := sha256.New()
+	run.Write(fingerprint)
+	fmt.Fprintf(run, "\npatterns %q\n", patterns)
+	for _, pkg := range pkgs {
+		fmt.Fprintf(run, "root %s %s\n", pkg.ID, key(pkg))
+	}
+	c.run = hex.EncodeToString(run.Sum(nil))
+	return c, nil
+}
+
+// cacheFingerprint identifies everything besides the packages' files that
+// shapes a result: the analyzer itself, the build configuration and the
+// options analyze reads.
+func cacheFingerprint(cfg *packages.Config, opts Options) ([]byte, error) {
+	// The overlay is hashed with the files it replaces, and the options
+	// applied after analysis are left out so that they share results.
+	opts.Overlay = nil
+	opts.CacheDir = ""
+	opts.Workers = 0
+	opts.ExportedOnly = false
+	opts.Implements = nil
+	opts.ImplementsAll = false
+	opts.MaxParams = 0
+	opts.NamingLint = false
+	opts.Stats = false
+	opts.Top = 0
+	opts.MaxMethods = 0
+	opts.GroupPackages = false
+	opts.FailOn = nil
+	opts.MinDocCoverage = 0
+
+	// A development build reports the same GeneratorVersion for any code,
+	// so the executable's identity stands in for it.
+	var executable string
+	if name, err := os.Executable(); err == nil {
+		if info, err := os.Stat(name); err == nil {
+			executable = fmt.Sprintf("%s %d %d", name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+
+	env := make(map[string]string)
+	for _, name := range []string{"GOOS", "GOARCH", "GOFLAGS", "GOEXPERIMENT", "CGO_ENABLED"} {
+		env[name] = os.Getenv(name)
+	}
+	for _, kv := range cfg.Env {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			if _, known := env[name]; known {
+				env[name] = value
+			}
+		}
+	}
+
+	return json.Marshal(struct {
+		SchemaVersion    string
+		GeneratorVersion string
+		Executable       string
+		Dir              string
+		BuildFlags       []string
+		Tests            bool
+		Env              map[string]string
+		Options          Options
+	}{SchemaVersion, GeneratorVersion, executable, cfg.Dir, cfg.BuildFlags, cfg.Tests, env, opts})
+}
+
+// goWorkFile returns the go.work file the go command uses for cfg, or ""
+// outside a workspace: the one GOWORK names, or else the first found in
+// cfg.Dir or its parents.
+func goWorkFile(cfg *packages.Config) string {
+	work, set := os.LookupEnv("GOWORK")
+	for _, kv := range cfg.Env {
+		if value, ok := strings.CutPrefix(kv, "GOWORK="); ok {
+			work, set = value, true
+		}
+	}
+	if set && work != "" {
+		if work == "off" {
+			return ""
+		}
+		return work
+	}
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+			return filepath.Join(dir, "go.work")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// fileSum hashes a file's contents, preferring those in overlay. Files that
+// cannot be read hash their error instead, which changes once they can.
+func fileSum(filename string, overlay map[string][]byte) []byte {
+	h := sha256.New()
+	if src, ok := overlay[filename]; ok {
+		h.Write(src)
+	} else if src, err := os.ReadFile(filename); err == nil {
+		h.Write(src)
+	} else {
+		fmt.Fprintf(h, "error: %v", err)
+	}
+	return h.Sum(nil)
+}
+
+// result returns the result of an earlier run of the same packages.
+func (c *resultCache) result() (AnalysisResult, bool) {
+	var result AnalysisResult
+	ok := c.load("results", c.run, &result)
+	return result, ok
+}
+
+func (c *resultCache) storeResult(result AnalysisResult) {
+	c.store("results", c.run, result)
+}
+
+// sections returns the packageSections cached for pkg.
+func (c *resultCache) sections(pkg *packages.Package) (packageSections, bool) {
+	var s packageSections
+	key, ok := c.keys[pkg.ID]
+	if !ok {
+		return s, false
+	}
+	return s, c.load("packages", key, &s)
+}
+
+func (c *resultCache) storeSections(pkg *packages.Package, s packageSections) {
+	if key, ok := c.keys[pkg.ID]; ok {
+		c.store("packages", key, s)
+	}
+}
+
+func (c *resultCache) load(kind, key string, v any) bool {
+	data, err := os.ReadFile(filepath.Join(c.dir, kind, key+".json"))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// store writes v through a temporary file, so that concurrent runs never
+// read a partial entry. Failing to cache is not an analysis error, so it is
+// only logged.
+func (c *resultCache) store(kind, key string, v any) {
+	if err := c.write(kind, key, v); err != nil {
+		log.Printf("Error writing cache: %v", err)
+	}
+}
+
+func (c *resultCache) write(kind, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(c.dir, kind)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, key+".json"))
+}
@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// sourceIndex holds what the analysis needs from the syntax trees but not
+// from go/types: doc comments by the position of the declared name, and
+// build constraints and generated-code markers by file name. All packages
+// from one load share a FileSet, so positions are unique across them.
+type sourceIndex struct {
+	docs map[token.Pos]*ast.CommentGroup
+	// ends holds the end of each declaration by the position of its name.
+	ends        map[token.Pos]token.Pos
+	constraints map[string][]string
+	generated   map[string]bool
+}
+
+func buildSourceIndex(pkgs []*packages.Package) *sourceIndex {
+	src := &sourceIndex{
+		docs:        make(map[token.Pos]*ast.CommentGroup),
+		ends:        make(map[token.Pos]token.Pos),
+		constraints: make(map[string][]string),
+		generated:   make(map[string]bool),
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			if constraints := fileConstraints(file); len(constraints) > 0 {
+				src.constraints[filename] = constraints
+			}
+			if ast.IsGenerated(file) {
+				src.generated[filename] = true
+			}
+
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					src.addDoc(decl.Name, decl.Doc)
+					src.ends[decl.Name.Pos()] = decl.End()
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						typeSpec, ok := spec.(*ast.TypeSpec)
+						if !ok {
+							continue
+						}
+
+						// An unparenthesized declaration keeps its comment
+						// on the GenDecl.
+						doc := typeSpec.Doc
+						if doc == nil && !decl.Lparen.IsValid() {
+							doc = decl.Doc
+						}
+						src.addDoc(typeSpec.Name, doc)
+						src.ends[typeSpec.Name.Pos()] = typeSpec.End()
+
+						switch t := typeSpec.Type.(type) {
+						case *ast.InterfaceType:
+							for _, field := range t.Methods.List {
+								for _, name := range field.Names {
+									src.addDoc(name, field.Doc)
+									src.ends[name.Pos()] = field.End()
+								}
+							}
+						case *ast.StructType:
+							// Fields are often documented by a line
+							// comment instead.
+							for _, field := range t.Fields.List {
+								doc := field.Doc
+								if doc == nil {
+									doc = field.Comment
+								}
+								names := field.Names
+								if name := embeddedName(field.Type); len(names) == 0 && name != nil {
+									names = []*ast.Ident{name}
+								}
+								for _, name := range names {
+									src.addDoc(name, doc)
+									src.ends[name.Pos()] = field.End()
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return src
+}
+
+func (src *sourceIndex) addDoc(name *ast.Ident, doc *ast.CommentGroup) {
+	if doc != nil {
+		src.docs[name.Pos()] = doc
+	}
+}
+
+// position returns the position of the declaration whose name is at pos,
+// extending to its end when it is one of the analyzed packages'.
+func (src *sourceIndex) position(fset *token.FileSet, pos token.Pos) Position {
+	return newPosition(fset, pos, src.ends[pos])
+}
+
+// doc returns the text of the doc comment for the name declared at pos, or
+// "" if there is none.
+func (src *sourceIndex) doc(pos token.Pos) string {
+	if doc, ok := src.docs[pos]; ok {
+		return strings.TrimSpace(doc.Text())
+	}
+	return ""
+}
+
+// embeddedName returns the type name of an embedded field of type expr,
+// where go/types places the field, or nil.
+func embeddedName(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// deprecation returns the text of the "Deprecated:" paragraph of the doc
+// comment for the name declared at pos, and whether there is one.
+func (src *sourceIndex) deprecation(pos token.Pos) (string, bool) {
+	doc, ok := src.docs[pos]
+	if !ok {
+		return "", false
+	}
+
+	for _, paragraph := range strings.Split(doc.Text(), "\n\n") {
+		if note, ok := strings.CutPrefix(paragraph, "Deprecated:"); ok {
+			return strings.Join(strings.Fields(note), " "), true
+		}
+	}
+	return "", false
+}
+
+// collectDeprecated lists the deprecated interfaces, structs and methods in
+// result.
+func collectDeprecated(result AnalysisResult) []Declaration {
+	deprecated := make([]Declaration, 0)
+	for _, iface := range result.Interfaces {
+		if iface.Deprecated {
+			deprecated = append(deprecated, interfaceDeclaration(iface))
+		}
+	}
+	for _, strct := range result.Structs {
+		if strct.Deprecated {
+			deprecated = append(deprecated, Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position})
+		}
+	}
+	forEachMethod(result, func(owner string, pkg string, method MethodInfo) {
+		if method.Deprecated {
+			deprecated = append(deprecated, Declaration{Name: owner + "." + method.Name, Package: pkg, Position: method.Position})
+		}
+	})
+	for _, fn := range result.Functions {
+		if fn.Deprecated {
+			deprecated = append(deprecated, Declaration{Name: fn.Name, Package: fn.Package, Position: fn.Position})
+		}
+	}
+	return deprecated
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeDot emits the implementation graph of result as a Graphviz digraph:
+// structs point to the interfaces they implement, with hollow arrows, and
+// to the types they embed, with diamonds. Node ids are qualified names, and
+// nodes carry their package and position as attributes and tooltip.
+func writeDot(w io.Writer, result AnalysisResult) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// schemaVersion: %s, generatorVersion: %s\n", result.SchemaVersion, result.GeneratorVersion)
+	fmt.Fprintln(bw, "digraph implementations {")
+	fmt.Fprintln(bw, "\trankdir=BT;")
+	fmt.Fprintln(bw, "\tnode [fontname=\"Helvetica\"];")
+
+	seen := make(map[string]bool)
+	writeNode := func(name, pkg string, pos Position, shape string) string {
+		id := pkg + "." + name
+		if seen[id] {
+			return id
+		}
+		seen[id] = true
+		position := fmt.Sprintf("%s:%d", pos.Path, pos.Line)
+		fmt.Fprintf(bw, "\t%s [label=%s, shape=%s, package=%s, position=%s, tooltip=%s];\n",
+			dotString(id), dotString(name), shape, dotString(pkg), dotString(position),
+			dotString(pkg+"\n"+position))
+		return id
+	}
+
+	for _, iface := range result.Interfaces {
+		writeNode(iface.Name, iface.Package, iface.Position, "ellipse")
+	}
+	for _, iface := range result.ExternalInterfaces {
+		writeNode(iface.Name, iface.Package, iface.Position, "ellipse")
+	}
+	for _, strct := range result.Structs {
+		writeNode(strct.Name, strct.Package, strct.Position, "box")
+	}
+
+	names := newTypeNames(result)
+	for _, strct := range result.Structs {
+		id := strct.Package + "." + strct.Name
+		for _, iface := range strct.ImplementedInterfaces {
+			target := writeNode(iface.Name, iface.Package, iface.Position, "ellipse")
+			fmt.Fprintf(bw, "\t%s -> %s [arrowhead=empty, style=dashed];\n", dotString(id), dotString(target))
+		}
+		// Embedded types may live outside the analyzed packages, so they
+		// are drawn plain, without position.
+		for _, embedded := range strct.EmbeddedTypes {
+			target := names.resolve(embedded, strct.Package)
+			if !seen[target] {
+				seen[target] = true
+				fmt.Fprintf(bw, "\t%s [label=%s, shape=box, style=dotted];\n", dotString(target), dotString(target))
+			}
+			fmt.Fprintf(bw, "\t%s -> %s [arrowhead=diamond, label=%s];\n", dotString(id), dotString(target), dotString("embeds"))
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// typeNames resolves the type names in a result, as rendered in any
+// -type-style, to the qualified names of its interfaces and structs.
+type typeNames map[string]bool
+
+func newTypeNames(result AnalysisResult) typeNames {
+	names := make(typeNames)
+	for _, iface := range result.Interfaces {
+		names[iface.Package+"."+iface.Name] = true
+	}
+	for _, strct := range result.Structs {
+		names[strct.Package+"."+strct.Name] = true
+	}
+	return names
+}
+
+// resolve returns the qualified name of the type rendered as name in the
+// package from, ignoring pointers and type arguments. Names of types outside
+// the result are returned as they are.
+func (names typeNames) resolve(name, from string) string {
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	if names[name] {
+		return name
+	}
+	if names[from+"."+name] {
+		return from + "." + name
+	}
+	// A short name qualifies by package name, the last path element.
+	for _, qualified := range sortedKeys(names) {
+		if strings.HasSuffix(qualified, "/"+name) {
+			return qualified
+		}
+	}
+	return name
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import "go/types"
+
+// EmbeddedType is a type embedded in a struct. Methods promoted from a type
+// embedded as a pointer panic when the field is nil. ID identifies the
+// declaration of the type embedded, generic ones included, like the IDs of
+// interfaces and structs.
+type EmbeddedType struct {
+	ID                string `json:"id"`
+	Type              string `json:"type"`
+	EmbeddedAsPointer bool   `json:"embeddedAsPointer,omitempty"`
+}
+
+// embeddedType describes the type t of an embedded field, rendered as typ.
+func embeddedType(t types.Type, typ string) EmbeddedType {
+	embedded := EmbeddedType{Type: typ}
+	if ptr, ok := t.(*types.Pointer); ok {
+		embedded.EmbeddedAsPointer = true
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		embedded.ID = typeID(named.Obj())
+	}
+	return embedded
+}
+
+// promotedThroughPointer reports whether the method selected from recv by
+// index, as given by types.Selection.Index, is reached through an embedded
+// pointer field.
+func promotedThroughPointer(recv types.Type, index []int) bool {
+	t := recv
+	for _, i := range index[:len(index)-1] {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		strct, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			return false
+		}
+		t = strct.Field(i).Type()
+		if _, ok := t.(*types.Pointer); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"context"
+	"go/types"
+	"slices"
+	"testing"
+)
+
+// models.Document embeds Timestamps by value and *Node by pointer.
+
+func TestPromotedThroughPointer(t *testing.T) {
+	models := fixturePackage(t, "models")
+	document := fixtureType(t, models, "Document")
+	tests := []struct {
+		recv   types.Type
+		method string
+		want   bool
+	}{
+		{document, "Age", false},
+		{document, "Next", true},
+		{document, "SetNext", true},
+		{types.NewPointer(document), "Age", false},
+		{types.NewPointer(document), "Next", true},
+		{types.NewPointer(document), "SetNext", true},
+		{fixtureType(t, models, "Timestamps"), "Age", false},
+		{types.NewPointer(fixtureType(t, models, "Node")), "Next", false},
+	}
+	for _, tt := range tests {
+		t.Run(types.TypeString(tt.recv, types.RelativeTo(models.Types))+"."+tt.method, func(t *testing.T) {
+			sel := types.NewMethodSet(tt.recv).Lookup(models.Types, tt.method)
+			if sel == nil {
+				t.Fatalf("no method %s", tt.method)
+			}
+			if got := promotedThroughPointer(sel.Recv(), sel.Index()); got != tt.want {
+				t.Errorf("promotedThroughPointer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeEmbeddedAsPointer(t *testing.T) {
+	result, err := New(func(opts *Options) {
+		opts.TypeStyle = typeStyleRelative
+	}).Analyze(context.Background(), fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := fixtureStruct(t, result, "go-analyzer-test/internal/models", "Document")
+
+	wantEmbedded := []EmbeddedType{
+		{ID: "go-analyzer-test/internal/models.Timestamps", Type: "Timestamps"},
+		{ID: "go-analyzer-test/internal/models.Node", Type: "*Node", EmbeddedAsPointer: true},
+	}
+	if !slices.Equal(document.Embedded, wantEmbedded) {
+		t.Errorf("Embedded = %+v, want %+v", document.Embedded, wantEmbedded)
+	}
+
+	tests := []struct {
+		method                   string
+		promoted, throughPointer bool
+	}{
+		{"Age", true, false},
+		{"Next", true, true},
+		{"SetNext", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			i := slices.IndexFunc(document.Methods, func(method MethodInfo) bool { return method.Name == tt.method })
+			if i < 0 {
+				t.Fatalf("no method %s", tt.method)
+			}
+			method := document.Methods[i]
+			if method.Promoted != tt.promoted || method.PromotedThroughPointer != tt.throughPointer {
+				t.Errorf("Promoted, PromotedThroughPointer = %v, %v, want %v, %v",
+					method.Promoted, method.PromotedThroughPointer, tt.promoted, tt.throughPointer)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// EmptyStructUse is a map or channel type whose values carry no data, such
+// as map[string]struct{} used as a set or chan struct{} used for signaling.
+type EmptyStructUse struct {
+	Package string `json:"package"`
+	// Kind is "map" or "chan".
+	Kind     string   `json:"kind"`
+	Type     string   `json:"type"`
+	Position Position `json:"position"`
+}
+
+func isEmptyStruct(t types.Type) bool {
+	strct, ok := t.Underlying().(*types.Struct)
+	return ok && strct.NumFields() == 0
+}
+
+// findEmptyStructUses returns the map and channel type expressions in pkg
+// whose element type is an empty struct.
+func findEmptyStructUses(pkg *packages.Package, style typeStyle, skipFile func(filename string) bool) []EmptyStructUse {
+	uses := make([]EmptyStructUse, 0)
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			var kind string
+			var elem types.Type
+			switch n := n.(type) {
+			case *ast.MapType:
+				if t, ok := pkg.TypesInfo.TypeOf(n).(*types.Map); ok {
+					kind, elem = "map", t.Elem()
+				}
+			case *ast.ChanType:
+				if t, ok := pkg.TypesInfo.TypeOf(n).(*types.Chan); ok {
+					kind, elem = "chan", t.Elem()
+				}
+			}
+			if elem == nil || !isEmptyStruct(elem) {
+				return true
+			}
+
+			uses = append(uses, EmptyStructUse{
+				Package:  pkg.PkgPath,
+				Kind:     kind,
+				Type:     style.typeString(pkg.TypesInfo.TypeOf(n.(ast.Expr)), pkg.Types),
+				Position: newPosition(pkg.Fset, n.Pos(), n.End()),
+			})
+			return true
+		})
+	}
+	return uses
+}
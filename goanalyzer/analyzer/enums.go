@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Enum is a const block enumerating the values of a named type with iota:
+//
+//	type Color int
+//
+//	const (
+//		Red Color = iota
+//		Green
+//	)
+type Enum struct {
+	Type    Declaration  `json:"type"`
+	Members []EnumMember `json:"members"`
+}
+
+type EnumMember struct {
+	Name     string   `json:"name"`
+	Value    string   `json:"value"`
+	Position Position `json:"position"`
+}
+
+// findEnums returns the enums declared in pkg, members in source order. A
+// block using iota for several types yields one enum per type, and a type
+// enumerated in several blocks yields one enum per block.
+func findEnums(pkg *packages.Package, skipFile func(filename string) bool) []Enum {
+	enums := make([]Enum, 0)
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST || !genDecl.Lparen.IsValid() || !usesIota(genDecl) {
+				continue
+			}
+
+			byType := make(map[*types.TypeName]int)
+			var block []Enum
+			for _, spec := range genDecl.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					constant, ok := pkg.TypesInfo.Defs[name].(*types.Const)
+					if !ok || name.Name == "_" {
+						continue
+					}
+					named, ok := constant.Type().(*types.Named)
+					if !ok || named.Obj().Pkg() != pkg.Types {
+						continue
+					}
+
+					i, ok := byType[named.Obj()]
+					if !ok {
+						i = len(block)
+						byType[named.Obj()] = i
+						block = append(block, Enum{
+							Type: Declaration{
+								Name:     named.Obj().Name(),
+								Package:  pkg.PkgPath,
+								Position: newPosition(pkg.Fset, named.Obj().Pos(), token.NoPos),
+							},
+							Members: make([]EnumMember, 0),
+						})
+					}
+					block[i].Members = append(block[i].Members, EnumMember{
+						Name:     name.Name,
+						Value:    constant.Val().ExactString(),
+						Position: newPosition(pkg.Fset, name.Pos(), name.End()),
+					})
+				}
+			}
+			enums = append(enums, block...)
+		}
+	}
+	return enums
+}
+
+func usesIota(decl *ast.GenDecl) bool {
+	found := false
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == "iota" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,14 @@
+package analyzer
+
+import "go/types"
+
+var (
+	errorType      = types.Universe.Lookup("error").Type()
+	errorInterface = errorType.Underlying().(*types.Interface)
+)
+
+// implementsError reports whether values of t, or pointers to them, satisfy
+// the built-in error interface.
+func implementsError(t types.Type) bool {
+	return types.Implements(t, errorInterface) || types.Implements(types.NewPointer(t), errorInterface)
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type ExampleInfo struct {
+	Name     string   `json:"name"`
+	Package  string   `json:"package"`
+	Kind     string   `json:"kind"`
+	Target   string   `json:"target,omitempty"`
+	Position Position `json:"position"`
+}
+
+// isTestVariant reports whether pkg is one of the extra packages loaded
+// when tests are included: "p [p.test]", "p_test [p.test]" or "p.test".
+func isTestVariant(pkg *packages.Package) bool {
+	return strings.Contains(pkg.ID, " [") || strings.HasSuffix(pkg.ID, ".test")
+}
+
+// extractExamples finds the Example functions in the _test.go files of pkg
+// and links each one to the package, function, type or method it documents,
+// following the naming rules of go doc.
+func extractExamples(pkg *packages.Package, includeGenerated bool) []ExampleInfo {
+	examples := make([]ExampleInfo, 0)
+
+	// External test packages document the package under test; when they
+	// do not import it only package examples can be linked.
+	target := pkg.Types
+	targetPath := strings.TrimSuffix(pkg.PkgPath, "_test")
+	if targetPath != pkg.PkgPath {
+		target = nil
+		if imported, ok := pkg.Imports[targetPath]; ok {
+			target = imported.Types
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		pos := pkg.Fset.Position(file.Pos())
+		if !strings.HasSuffix(pos.Filename, "_test.go") || (!includeGenerated && ast.IsGenerated(file)) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || !strings.HasPrefix(funcDecl.Name.Name, "Example") {
+				continue
+			}
+
+			kind, name := exampleTarget(funcDecl.Name.Name, target)
+			if kind == "" {
+				continue
+			}
+
+			examples = append(examples, ExampleInfo{
+				Name:     funcDecl.Name.Name,
+				Package:  targetPath,
+				Kind:     kind,
+				Target:   name,
+				Position: newPosition(pkg.Fset, funcDecl.Pos(), funcDecl.End()),
+			})
+		}
+	}
+
+	return examples
+}
+
+// exampleTarget parses an example name such as "ExampleRepository_Create" or
+// "ExampleNew_withOptions" and returns the kind and name of the documented
+// declaration. It returns an empty kind when the name matches nothing in pkg.
+func exampleTarget(exampleName string, pkg *types.Package) (kind, name string) {
+	name = strings.TrimPrefix(exampleName, "Example")
+	if name == "" {
+		return "package", ""
+	}
+	if !strings.HasPrefix(name, "_") && !startsUpper(name) {
+		return "", ""
+	}
+
+	parts := strings.Split(strings.TrimPrefix(name, "_"), "_")
+	// A trailing lower-case part is a free-form suffix, e.g. Example_second.
+	if last := parts[len(parts)-1]; !startsUpper(last) {
+		parts = parts[:len(parts)-1]
+	}
+
+	if len(parts) == 0 {
+		return "package", ""
+	}
+	if pkg == nil {
+		return "", ""
+	}
+
+	switch len(parts) {
+	case 1:
+		switch pkg.Scope().Lookup(parts[0]).(type) {
+		case *types.TypeName:
+			return "type", parts[0]
+		case *types.Func:
+			return "func", parts[0]
+		}
+	case 2:
+		if obj, ok := pkg.Scope().Lookup(parts[0]).(*types.TypeName); ok {
+			method, _, _ := types.LookupFieldOrMethod(obj.Type(), true, pkg, parts[1])
+			if _, ok := method.(*types.Func); ok {
+				return "method", parts[0] + "." + parts[1]
+			}
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"go/types"
+	"io"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeExplanation is what -explain prints about one type, gathered while
+// its package is type-checked.
+type TypeExplanation struct {
+	Name     string
+	Package  string
+	Kind     string
+	Position Position
+	Doc      string
+	// Underlying is set for kinds other than struct and interface.
+	Underlying string
+	Fields     []string
+	Embeds     []string
+	Methods    []string
+}
+
+// matchesExplain reports whether obj is the type -explain names, either
+// plain or qualified by import path or its last elements, as in
+// repositories.Cache.
+func matchesExplain(obj *types.TypeName, name string) bool {
+	return obj.Pkg() != nil && MatchesName(obj.Pkg().Path(), obj.Name(), name)
+}
+
+// explainType describes obj. Methods are those of the pointer type, so
+// methods promoted from embedded fields are included.
+func explainType(obj *types.TypeName, pkg *packages.Package, src *sourceIndex, style typeStyle) TypeExplanation {
+	qualifier := style.qualifier(pkg.Types)
+	explained := TypeExplanation{
+		Name:     obj.Name(),
+		Package:  pkg.PkgPath,
+		Position: src.position(pkg.Fset, obj.Pos()),
+	}
+	explained.Doc = src.doc(obj.Pos())
+
+	var methods *types.MethodSet
+	switch underlying := obj.Type().Underlying().(type) {
+	case *types.Struct:
+		explained.Kind = "struct"
+		for i := 0; i < underlying.NumFields(); i++ {
+			field := underlying.Field(i)
+			if field.Anonymous() {
+				explained.Embeds = append(explained.Embeds, types.TypeString(field.Type(), qualifier))
+				continue
+			}
+			explained.Fields = append(explained.Fields, field.Name()+" "+types.TypeString(field.Type(), qualifier))
+		}
+		methods = types.NewMethodSet(types.NewPointer(obj.Type()))
+	case *types.Interface:
+		explained.Kind = "interface"
+		for i := 0; i < underlying.NumEmbeddeds(); i++ {
+			explained.Embeds = append(explained.Embeds, types.TypeString(underlying.EmbeddedType(i), qualifier))
+		}
+		methods = types.NewMethodSet(obj.Type())
+	default:
+		explained.Kind = "type"
+		if obj.IsAlias() {
+			explained.Kind = "alias"
+		}
+		explained.Underlying = types.TypeString(underlying, qualifier)
+		methods = types.NewMethodSet(types.NewPointer(obj.Type()))
+	}
+
+	for i := 0; i < methods.Len(); i++ {
+		explained.Methods = append(explained.Methods, types.ObjectString(methods.At(i).Obj(), qualifier))
+	}
+	return explained
+}
+
+// WriteExplanation prints the type result.Explained holds, with the
+// interfaces it implements or the structs implementing it. It fails when no
+// type, or more than one, matched.
+func WriteExplanation(w io.Writer, result AnalysisResult, name string) error {
+	explained, err := lookupDeclaration(result.Explained, "type", name, func(explained TypeExplanation) (string, string) {
+		return explained.Package, explained.Name
+	})
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s.%s @ %s:%d\n", explained.Kind, explained.Package, explained.Name, explained.Position.Path, explained.Position.Line)
+	if explained.Underlying != "" {
+		fmt.Fprintf(bw, "\n  underlying %s\n", explained.Underlying)
+	}
+	if explained.Doc != "" {
+		fmt.Fprintf(bw, "\n%s\n", indent(explained.Doc))
+	}
+
+	var related []string
+	var relation string
+	for _, strct := range result.Structs {
+		if explained.Kind == "struct" && strct.Package == explained.Package && strct.Name == explained.Name {
+			relation = "Implements"
+			for _, iface := range strct.ImplementedInterfaces {
+				related = append(related, iface.Package+"."+iface.Name)
+			}
+		}
+		if explained.Kind == "interface" {
+			relation = "Implemented by"
+			for _, iface := range strct.ImplementedInterfaces {
+				if iface.Package == explained.Package && iface.Name == explained.Name {
+					related = append(related, strct.Package+"."+strct.Name)
+				}
+			}
+		}
+	}
+
+	writeList(bw, "Fields", explained.Fields)
+	writeList(bw, "Embeds", explained.Embeds)
+	writeList(bw, "Methods", explained.Methods)
+	writeList(bw, relation, related)
+	return bw.Flush()
+}
+
+func writeList(w io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(w, "  %s\n", item)
+	}
+}
+
+func indent(text string) string {
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ")
+}
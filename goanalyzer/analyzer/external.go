@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// externalInterfaces matches structs against the interfaces exported by the
+// packages they import, such as io.Reader, and against the fixed stdlib
+// interfaces, and keeps the description of every interface matched. It is
+// safe for concurrent use.
+type externalInterfaces struct {
+	fset   *token.FileSet
+	src    *sourceIndex
+	cache  *typeCache
+	style  typeStyle
+	stdlib []externalInterface
+
+	mu        sync.Mutex
+	byPackage map[*types.Package][]externalInterface
+	matched   map[string]InterfaceInfo
+}
+
+type externalInterface struct {
+	iface *types.Interface
+	info  InterfaceInfo
+}
+
+func newExternalInterfaces(fset *token.FileSet, src *sourceIndex, cache *typeCache, style typeStyle, stdlib []externalInterface) *externalInterfaces {
+	return &externalInterfaces{
+		fset:      fset,
+		src:       src,
+		cache:     cache,
+		style:     style,
+		stdlib:    stdlib,
+		byPackage: make(map[*types.Package][]externalInterface),
+		matched:   make(map[string]InterfaceInfo),
+	}
+}
+
+// match adds the stdlib interfaces and those from imports that named, or a
+// pointer to it, implements to strct.
+func (e *externalInterfaces) match(strct *StructInfo, named *types.Named, imports []*types.Package) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	subject := genericSubject(named)
+	e.matchAll(strct, subject, e.stdlib)
+	for _, imported := range imports {
+		e.matchAll(strct, subject, e.declaredIn(imported))
+	}
+}
+
+func (e *externalInterfaces) matchAll(strct *StructInfo, t types.Type, candidates []externalInterface) {
+	for _, ext := range candidates {
+		if e.cache.implements(t, ext.iface) && !implementsDeclared(strct, ext.info) {
+			addImplemented(strct, ext.info, receiverKind(t, ext.iface, e.cache))
+			e.matched[ext.info.Package+"."+ext.info.Name] = ext.info
+		}
+	}
+}
+
+// declaredIn returns the exported, non-generic interfaces with methods
+// declared in pkg. Their positions come from export data, so they point
+// into the module cache or GOROOT.
+func (e *externalInterfaces) declaredIn(pkg *types.Package) []externalInterface {
+	if found, ok := e.byPackage[pkg]; ok {
+		return found
+	}
+
+	found := make([]externalInterface, 0)
+	// processInterface only needs the package for its path and file set.
+	declaring := &packages.Package{PkgPath: pkg.Path(), Fset: e.fset}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		iface, ok := named.Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 || !iface.IsMethodSet() {
+			continue
+		}
+		if info := processInterface(obj, declaring, e.src, e.style); info != nil {
+			found = append(found, externalInterface{iface: iface, info: *info})
+		}
+	}
+	e.byPackage[pkg] = found
+	return found
+}
+
+// interfaces returns the matched interfaces ordered by package and name.
+func (e *externalInterfaces) interfaces() []InterfaceInfo {
+	list := make([]InterfaceInfo, 0, len(e.matched))
+	for _, info := range e.matched {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Package != list[j].Package {
+			return list[i].Package < list[j].Package
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"go/token"
+	"sort"
+)
+
+// minExtractMethods is the number of uncaptured methods that makes a struct
+// worth an extracted interface.
+const minExtractMethods = 2
+
+// ExtractInterfaceCandidate supports the "extract interface" refactoring for
+// a struct. ExactMatches are the implemented interfaces declaring exactly
+// the struct's exported methods; UncapturedMethods are the exported methods,
+// other than getters and setters, that no implemented interface declares.
+type ExtractInterfaceCandidate struct {
+	Struct            Declaration   `json:"struct"`
+	ExactMatches      []Declaration `json:"exactMatches,omitempty"`
+	UncapturedMethods []string      `json:"uncapturedMethods,omitempty"`
+}
+
+// findExtractInterfaceCandidates lists the structs of result with an exact
+// interface match or at least minExtractMethods uncaptured methods.
+func findExtractInterfaceCandidates(result AnalysisResult) []ExtractInterfaceCandidate {
+	interfaces := make(map[string]InterfaceInfo, len(result.Interfaces))
+	for _, iface := range result.Interfaces {
+		interfaces[iface.Package+"."+iface.Name] = iface
+	}
+
+	candidates := make([]ExtractInterfaceCandidate, 0)
+	for _, strct := range result.Structs {
+		exported := make(map[string]bool)
+		for _, method := range strct.Methods {
+			if token.IsExported(method.Name) {
+				exported[method.Name] = true
+			}
+		}
+		if len(exported) == 0 {
+			continue
+		}
+
+		candidate := ExtractInterfaceCandidate{
+			Struct: Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position},
+		}
+		captured := make(map[string]bool)
+		for _, implemented := range strct.ImplementedInterfaces {
+			iface, ok := interfaces[implemented.Package+"."+implemented.Name]
+			if !ok {
+				continue
+			}
+			declared := 0
+			for _, method := range iface.Methods {
+				captured[method.Name] = true
+				if exported[method.Name] {
+					declared++
+				}
+			}
+			if declared == len(exported) && len(iface.Methods) == len(exported) {
+				candidate.ExactMatches = append(candidate.ExactMatches, implemented)
+			}
+		}
+
+		for _, method := range strct.Methods {
+			if exported[method.Name] && !captured[method.Name] && method.Accessor == "" {
+				candidate.UncapturedMethods = append(candidate.UncapturedMethods, method.Name)
+			}
+		}
+		sort.Strings(candidate.UncapturedMethods)
+		if len(candidate.UncapturedMethods) < minExtractMethods {
+			candidate.UncapturedMethods = nil
+		}
+
+		if len(candidate.ExactMatches) > 0 || len(candidate.UncapturedMethods) > 0 {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
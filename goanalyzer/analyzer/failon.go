@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FailOnErrors is the -fail-on category for package loading and type
+// checking errors.
+const FailOnErrors = "errors"
+
+// FailOnCategories returns the names -fail-on accepts: errors, and the
+// sections that list problems.
+func FailOnCategories() []string {
+	names := []string{FailOnErrors}
+	for name, section := range sections {
+		if section.count != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseFailOn validates a comma-separated list of -fail-on categories.
+func ParseFailOn(list string) ([]string, error) {
+	var categories []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name != FailOnErrors && sections[name].count == nil {
+			return nil, fmt.Errorf("unknown category %q: must be one of %s", name, strings.Join(FailOnCategories(), ", "))
+		}
+		categories = append(categories, name)
+	}
+	return categories, nil
+}
+
+// failures describes the categories with entries in result.
+func failures(result AnalysisResult, categories []string) []string {
+	var failed []string
+	for _, name := range categories {
+		count := 0
+		if name == FailOnErrors {
+			count = len(result.Errors)
+		} else {
+			count = sections[name].count(result)
+		}
+		if count > 0 {
+			failed = append(failed, fmt.Sprintf("%s (%d)", name, count))
+		}
+	}
+	return failed
+}
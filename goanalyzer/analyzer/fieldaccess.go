@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// fieldAccess adds the methods among decls that read and write each field
+// of strct to fields, as listed by structFields. Accesses are found
+// through the receiver only, so this is best-effort: fields reached through
+// another variable holding the receiver, or through reflection, are not
+// seen. A field counts as written when it is assigned to, incremented, or
+// has its address taken, as when calling a method with a pointer receiver
+// on it; writes through a pointer or into a slice or map the field holds
+// are reads of the field. Promoted fields are attributed to the embedded
+// field they come from.
+func fieldAccess(fields []FieldInfo, strct *types.Struct, decls []*ast.FuncDecl, pkg *packages.Package) {
+	readBy := make(map[*types.Var]map[string]bool)
+	writtenBy := make(map[*types.Var]map[string]bool)
+	mark := func(access map[*types.Var]map[string]bool, field *types.Var, method string) {
+		if access[field] == nil {
+			access[field] = make(map[string]bool)
+		}
+		access[field][method] = true
+	}
+
+	for _, decl := range decls {
+		if len(decl.Recv.List) == 0 || len(decl.Recv.List[0].Names) == 0 {
+			continue
+		}
+		recv := pkg.TypesInfo.Defs[decl.Recv.List[0].Names[0]]
+		if recv == nil {
+			continue
+		}
+		method := decl.Name.Name
+
+		// receiverField returns the field of strct sel selects on the
+		// receiver, if any.
+		receiverField := func(sel *ast.SelectorExpr) *types.Var {
+			ident, ok := sel.X.(*ast.Ident)
+			if !ok || pkg.TypesInfo.Uses[ident] != recv {
+				return nil
+			}
+			selection, ok := pkg.TypesInfo.Selections[sel]
+			if !ok || selection.Kind() != types.FieldVal {
+				return nil
+			}
+			return strct.Field(selection.Index()[0])
+		}
+
+		// stored returns the receiver field whose storage e denotes.
+		var stored func(e ast.Expr) *ast.SelectorExpr
+		stored = func(e ast.Expr) *ast.SelectorExpr {
+			switch e := e.(type) {
+			case *ast.ParenExpr:
+				return stored(e.X)
+			case *ast.SelectorExpr:
+				if receiverField(e) != nil {
+					return e
+				}
+				if _, ok := pkg.TypesInfo.TypeOf(e.X).Underlying().(*types.Pointer); ok {
+					return nil
+				}
+				return stored(e.X)
+			case *ast.IndexExpr:
+				if _, ok := pkg.TypesInfo.TypeOf(e.X).Underlying().(*types.Array); ok {
+					return stored(e.X)
+				}
+			}
+			return nil
+		}
+
+		// Selectors that only write, as on the left of a plain assignment.
+		writeOnly := make(map[*ast.SelectorExpr]bool)
+		write := func(e ast.Expr, alsoRead bool) {
+			if sel := stored(e); sel != nil {
+				mark(writtenBy, receiverField(sel), method)
+				if !alsoRead && sel == astutil.Unparen(e) {
+					writeOnly[sel] = true
+				}
+			}
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if n.Tok != token.DEFINE {
+					for _, lhs := range n.Lhs {
+						write(lhs, n.Tok != token.ASSIGN)
+					}
+				}
+			case *ast.IncDecStmt:
+				write(n.X, true)
+			case *ast.UnaryExpr:
+				if n.Op == token.AND {
+					write(n.X, true)
+				}
+			case *ast.SelectorExpr:
+				// Calling a pointer method on a field takes its address.
+				selection, ok := pkg.TypesInfo.Selections[n]
+				if ok && selection.Kind() == types.MethodVal && !selection.Indirect() {
+					if _, ok := selection.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+						if _, ok := selection.Recv().Underlying().(*types.Pointer); !ok {
+							write(n.X, true)
+						}
+					}
+				}
+			}
+			return true
+		})
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok && !writeOnly[sel] {
+				if field := receiverField(sel); field != nil {
+					mark(readBy, field, method)
+				}
+			}
+			return true
+		})
+	}
+
+	for i := range fields {
+		field := strct.Field(i)
+		fields[i].ReadBy = sortedKeys(readBy[field])
+		fields[i].WrittenBy = sortedKeys(writtenBy[field])
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// structFields lists the fields of strct, the struct identified by owner,
+// in declaration order.
+func structFields(owner string, strct *types.Struct, pkg *packages.Package, src *sourceIndex, style typeStyle) []FieldInfo {
+	fields := make([]FieldInfo, 0, strct.NumFields())
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		info := FieldInfo{
+			ID:       owner + "." + field.Name(),
+			Name:     field.Name(),
+			Type:     style.typeString(field.Type(), pkg.Types),
+			Exported: field.Exported(),
+			Embedded: field.Anonymous(),
+			Tag:      strct.Tag(i),
+			Tags:     parseStructTag(strct.Tag(i)),
+			Position: src.position(pkg.Fset, field.Pos()),
+			Doc:      src.doc(field.Pos()),
+		}
+		if signature, ok := field.Type().Underlying().(*types.Signature); ok {
+			info.Signature = style.typeString(signature, pkg.Types)
+		}
+		fields = append(fields, info)
+	}
+	return fields
+}
+
+// parseStructTag splits a struct tag in the conventional format, such as
+// `json:"name,omitempty" db:"name"`, into its values by key. Parsing stops
+// at the first malformed pair, as reflect.StructTag.Lookup does.
+func parseStructTag(tag string) map[string]string {
+	var values map[string]string
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tag = tag[i+1:]
+
+		if values == nil {
+			values = make(map[string]string)
+		}
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+	return values
+}
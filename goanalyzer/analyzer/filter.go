@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"errors"
+	"go/token"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// filterImplementing keeps the structs implementing any (or, with requireAll,
+// every) interface in names. Names match either the bare interface name or
+// its package-qualified form, e.g. "example.com/pkg/store.Repository".
+func filterImplementing(structs []StructInfo, names []string, requireAll bool) []StructInfo {
+	filtered := make([]StructInfo, 0)
+	for _, strct := range structs {
+		matched := 0
+		for _, name := range names {
+			for _, iface := range strct.ImplementedInterfaces {
+				if iface.Name == name || iface.Package+"."+iface.Name == name {
+					matched++
+					break
+				}
+			}
+		}
+
+		if (requireAll && matched == len(names)) || (!requireAll && matched > 0) {
+			filtered = append(filtered, strct)
+		}
+	}
+	return filtered
+}
+
+func filterExported(interfaces []InterfaceInfo, structs []StructInfo) ([]InterfaceInfo, []StructInfo) {
+	exportedInterfaces := make([]InterfaceInfo, 0)
+	for _, iface := range interfaces {
+		if token.IsExported(iface.Name) {
+			exportedInterfaces = append(exportedInterfaces, iface)
+		}
+	}
+
+	exportedStructs := make([]StructInfo, 0)
+	for _, strct := range structs {
+		if token.IsExported(strct.Name) {
+			exportedStructs = append(exportedStructs, strct)
+		}
+	}
+	return exportedInterfaces, exportedStructs
+}
+
+// mainModulePath returns the path of the module declared by the go.mod the
+// packages were loaded from, or "" outside of module mode.
+func mainModulePath(pkgs []*packages.Package) string {
+	for _, pkg := range pkgs {
+		if pkg.Module != nil && pkg.Module.Main {
+			return pkg.Module.Path
+		}
+	}
+	return ""
+}
+
+func includePackage(pkg *packages.Package, modulePath string, opts Options) bool {
+	if opts.SkipVendor && (strings.HasPrefix(pkg.PkgPath, "vendor/") || strings.Contains(pkg.PkgPath, "/vendor/")) {
+		return false
+	}
+	// Test variants share the import path of the package under test.
+	path := strings.TrimSuffix(strings.TrimSuffix(pkg.PkgPath, ".test"), "_test")
+	if opts.OnlyModule && modulePath != "" {
+		if path != modulePath && !strings.HasPrefix(path, modulePath+"/") {
+			return false
+		}
+	}
+	if len(opts.Include) > 0 && !matchAnyPackage(opts.Include, path) {
+		return false
+	}
+	return !matchAnyPackage(opts.Exclude, path)
+}
+
+func matchAnyPackage(patterns []string, pkgPath string) bool {
+	for _, pattern := range patterns {
+		if matchPackage(pattern, pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPackage reports whether the import path pkgPath matches pattern:
+// a regular expression after "re:", matching anywhere in the path unless
+// anchored; a path ending in "/..." matching it and every path below; or a
+// glob, where "*" matches within one path element and a "**" element
+// matches any number of them.
+func matchPackage(pattern, pkgPath string) bool {
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		return err == nil && re.MatchString(pkgPath)
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")
+	}
+	return matchGlob(strings.Split(pattern, "/"), strings.Split(pkgPath, "/"))
+}
+
+func matchGlob(pattern, elems []string) bool {
+	if len(pattern) == 0 {
+		return len(elems) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(elems); i++ {
+			if matchGlob(pattern[1:], elems[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(elems) == 0 {
+		return false
+	}
+	matched, _ := path.Match(pattern[0], elems[0])
+	return matched && matchGlob(pattern[1:], elems[1:])
+}
+
+// CheckPackagePattern reports whether pattern is valid in Options.Include
+// and Exclude.
+func CheckPackagePattern(pattern string) error {
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		_, err := regexp.Compile(expr)
+		return err
+	}
+	if _, err := path.Match(strings.TrimSuffix(pattern, "/..."), ""); err != nil {
+		return err
+	}
+	if pattern == "" {
+		return errors.New("empty pattern")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+type TypeFingerprint struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Package  string   `json:"package"`
+	Position Position `json:"position"`
+	Methods  int      `json:"methods"`
+	// Fingerprint is the hex SHA-256 of the canonical method set; see
+	// canonicalMethodSet.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// FingerprintReport is the output of -format fingerprint: a hash per type
+// that changes exactly when the type's method set does.
+type FingerprintReport struct {
+	SchemaVersion    string            `json:"schemaVersion"`
+	GeneratorVersion string            `json:"generatorVersion"`
+	Types            []TypeFingerprint `json:"types"`
+}
+
+func buildFingerprints(result AnalysisResult) FingerprintReport {
+	report := FingerprintReport{
+		SchemaVersion:    result.SchemaVersion,
+		GeneratorVersion: result.GeneratorVersion,
+		Types:            make([]TypeFingerprint, 0, len(result.Interfaces)+len(result.Structs)),
+	}
+
+	add := func(kind, name, pkg string, pos Position, methods []MethodInfo) {
+		sum := sha256.Sum256([]byte(canonicalMethodSet(methods)))
+		report.Types = append(report.Types, TypeFingerprint{
+			Kind:        kind,
+			Name:        name,
+			Package:     pkg,
+			Position:    pos,
+			Methods:     len(methods),
+			Fingerprint: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	for _, iface := range result.Interfaces {
+		add("interface", iface.Name, iface.Package, iface.Position, iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		add("struct", strct.Name, strct.Package, strct.Position, strct.Methods)
+	}
+
+	return report
+}
+
+// canonicalMethodSet renders methods one per line, sorted by name, as
+// "Name(T1,T2)(R1,R2)". Types are fully qualified by import path, and
+// parameter names, positions and documentation are left out, so only
+// changes to the contract alter the result.
+func canonicalMethodSet(methods []MethodInfo) string {
+	lines := make([]string, 0, len(methods))
+	for _, method := range methods {
+		lines = append(lines, canonicalSignature(method.Name, method.Signature, method.Parameters, method.Variadic, method.ReturnTypes))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,41 @@
+package analyzer
+
+import "go/types"
+
+// FluentType is a type with chainable methods: methods returning the type
+// itself, or a pointer to it, as builders do.
+type FluentType struct {
+	Type    Declaration `json:"type"`
+	Methods []string    `json:"methods"`
+}
+
+// fluentMethods returns the names of the methods of t whose results include
+// t or *t. Generic types match whatever their type arguments are, so
+// Builder[T].With returning Builder[T] is chainable.
+func fluentMethods(t *types.Named) []string {
+	origin := t.Origin()
+	returnsSelf := func(result types.Type) bool {
+		if ptr, ok := result.(*types.Pointer); ok {
+			result = ptr.Elem()
+		}
+		named, ok := result.(*types.Named)
+		return ok && named.Origin() == origin
+	}
+
+	var names []string
+	methodSet := types.NewMethodSet(types.NewPointer(t))
+	if types.IsInterface(t) {
+		methodSet = types.NewMethodSet(t)
+	}
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+		results := method.Type().(*types.Signature).Results()
+		for j := 0; j < results.Len(); j++ {
+			if returnsSelf(results.At(j).Type()) {
+				names = append(names, method.Name())
+				break
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// FunctionInfo describes a package-level function. Functions whose first
+// result is a named type, or a pointer to one, look like constructors, such
+// as NewUserPostgresRepository; Constructs then names that type. The
+// optional body passes fill in the same fields as for methods.
+type FunctionInfo struct {
+	ID                 string       `json:"id"`
+	Name               string       `json:"name"`
+	Package            string       `json:"package"`
+	Position           Position     `json:"position"`
+	TypeParams         []TypeParam  `json:"typeParams,omitempty"`
+	Parameters         []ParamInfo  `json:"parameters"`
+	ReturnTypes        []string     `json:"returnTypes"`
+	Signature          string       `json:"signature,omitempty"`
+	ParamCount         int          `json:"paramCount"`
+	Variadic           bool         `json:"variadic,omitempty"`
+	ReturnCount        int          `json:"returnCount"`
+	Constructor        bool         `json:"constructor,omitempty"`
+	Constructs         *Declaration `json:"constructs,omitempty"`
+	GoroutineLaunches  []Position   `json:"goroutineLaunches,omitempty"`
+	Panics             []Position   `json:"panics,omitempty"`
+	Repanics           []Position   `json:"repanics,omitempty"`
+	TokenCount         int          `json:"tokenCount,omitempty"`
+	ReturnedErrorTypes []string     `json:"returnedErrorTypes,omitempty"`
+	Source             string       `json:"source,omitempty"`
+	Deprecated         bool         `json:"deprecated,omitempty"`
+	DeprecationNote    string       `json:"deprecationNote,omitempty"`
+	BuildConstraints   []string     `json:"buildConstraints,omitempty"`
+}
+
+// findFunctions returns the package-level functions of pkg, ordered by name.
+func findFunctions(pkg *packages.Package, src *sourceIndex, style typeStyle, skip func(types.Object) bool) []FunctionInfo {
+	functions := make([]FunctionInfo, 0)
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		fn, ok := scope.Lookup(name).(*types.Func)
+		if !ok || skip(fn) {
+			continue
+		}
+
+		signature := fn.Type().(*types.Signature)
+		info := FunctionInfo{
+			ID:          functionID(fn),
+			Name:        fn.Name(),
+			Package:     pkg.PkgPath,
+			Position:    src.position(pkg.Fset, fn.Pos()),
+			TypeParams:  typeParams(signature.TypeParams(), pkg.Types, style),
+			Parameters:  extractParams(signature, fn.Pkg(), style),
+			ReturnTypes: extractReturnTypes(signature, fn.Pkg(), style),
+			Signature:   canonicalTypes(signature),
+			ParamCount:  signature.Params().Len(),
+			Variadic:    signature.Variadic(),
+			ReturnCount: signature.Results().Len(),
+		}
+		info.DeprecationNote, info.Deprecated = src.deprecation(fn.Pos())
+		info.BuildConstraints = src.constraints[pkg.Fset.Position(fn.Pos()).Filename]
+		if constructed := constructedType(signature); constructed != nil {
+			info.Constructor = true
+			info.Constructs = &Declaration{
+				Name:     constructed.Name(),
+				Package:  constructed.Pkg().Path(),
+				Position: newPosition(pkg.Fset, constructed.Pos(), token.NoPos),
+			}
+		}
+		functions = append(functions, info)
+	}
+	return functions
+}
+
+// constructedType returns the named type a function returning signature
+// produces first, if any. Predeclared types such as error do not count.
+func constructedType(signature *types.Signature) *types.TypeName {
+	if signature.Results().Len() == 0 {
+		return nil
+	}
+	t := signature.Results().At(0).Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	return named.Origin().Obj()
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"go/types"
+)
+
+// TypeParam is a type parameter of a generic declaration, with its
+// constraint as written, such as "any", "comparable" or "~int | ~string".
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+	// ConstraintRef is set with Options.StructuredTypes.
+	ConstraintRef *TypeRef `json:"constraintRef,omitempty"`
+}
+
+// typeParams describes list, or returns nil for non-generic declarations.
+func typeParams(list *types.TypeParamList, from *types.Package, style typeStyle) []TypeParam {
+	if list.Len() == 0 {
+		return nil
+	}
+	params := make([]TypeParam, list.Len())
+	for i := range params {
+		param := list.At(i)
+		params[i] = TypeParam{
+			Name:       param.Obj().Name(),
+			Constraint: style.typeString(param.Constraint(), from),
+		}
+	}
+	return params
+}
+
+// genericSubject returns the type whose method set is checked against
+// interfaces: named itself, or for generic types named instantiated with
+// its own type parameters, so that its methods mention those rather than
+// the separate type parameters of each method's receiver.
+func genericSubject(named *types.Named) types.Type {
+	params := named.TypeParams()
+	if params.Len() == 0 {
+		return named
+	}
+	args := make([]types.Type, params.Len())
+	for i := range args {
+		args[i] = params.At(i)
+	}
+	inst, err := types.Instantiate(nil, named, args, false)
+	if err != nil {
+		return named
+	}
+	return inst
+}
+
+// inferImplemented reports whether t, or a pointer to it, implements the
+// generic interface for some type arguments, and returns them. They are
+// inferred by matching the interface's methods against t's methods of the
+// same names, then checked against the interface's constraints.
+func inferImplemented(t types.Type, generic *types.Named, cache *typeCache) ([]types.Type, bool) {
+	iface, ok := generic.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return nil, false
+	}
+
+	params := generic.TypeParams()
+	bound := make(map[*types.TypeParam]types.Type)
+	methods := cache.methodSet(types.NewPointer(t))
+	for i := 0; i < iface.NumMethods(); i++ {
+		want := iface.Method(i)
+		sel := methods.Lookup(want.Pkg(), want.Name())
+		if sel == nil {
+			return nil, false
+		}
+		have, ok := sel.Type().(*types.Signature)
+		if !ok || !unify(want.Type(), types.NewSignatureType(nil, nil, nil, have.Params(), have.Results(), have.Variadic()), params, bound) {
+			return nil, false
+		}
+	}
+
+	args := make([]types.Type, params.Len())
+	for i := range args {
+		arg, ok := bound[params.At(i)]
+		if !ok {
+			return nil, false
+		}
+		args[i] = arg
+	}
+	inst, err := types.Instantiate(nil, generic, args, true)
+	if err != nil {
+		return nil, false
+	}
+	instIface, ok := inst.Underlying().(*types.Interface)
+	if !ok || !cache.implements(t, instIface) {
+		return nil, false
+	}
+	return args, true
+}
+
+// unify matches want, which may mention the type parameters params, against
+// have, recording in bound the type each parameter stands for. It reports
+// whether they match.
+func unify(want, have types.Type, params *types.TypeParamList, bound map[*types.TypeParam]types.Type) bool {
+	if param, ok := want.(*types.TypeParam); ok && isParamOf(param, params) {
+		if prev, ok := bound[param]; ok {
+			return types.Identical(prev, have)
+		}
+		bound[param] = have
+		return true
+	}
+
+	switch want := want.(type) {
+	case *types.Pointer:
+		have, ok := have.(*types.Pointer)
+		return ok && unify(want.Elem(), have.Elem(), params, bound)
+	case *types.Slice:
+		have, ok := have.(*types.Slice)
+		return ok && unify(want.Elem(), have.Elem(), params, bound)
+	case *types.Array:
+		have, ok := have.(*types.Array)
+		return ok && want.Len() == have.Len() && unify(want.Elem(), have.Elem(), params, bound)
+	case *types.Map:
+		have, ok := have.(*types.Map)
+		return ok && unify(want.Key(), have.Key(), params, bound) && unify(want.Elem(), have.Elem(), params, bound)
+	case *types.Chan:
+		have, ok := have.(*types.Chan)
+		return ok && want.Dir() == have.Dir() && unify(want.Elem(), have.Elem(), params, bound)
+	case *types.Signature:
+		have, ok := have.(*types.Signature)
+		return ok && want.Variadic() == have.Variadic() &&
+			unifyTuples(want.Params(), have.Params(), params, bound) &&
+			unifyTuples(want.Results(), have.Results(), params, bound)
+	case *types.Named:
+		have, ok := have.(*types.Named)
+		if !ok || want.Origin() != have.Origin() || want.TypeArgs().Len() != have.TypeArgs().Len() {
+			return false
+		}
+		for i := 0; i < want.TypeArgs().Len(); i++ {
+			if !unify(want.TypeArgs().At(i), have.TypeArgs().At(i), params, bound) {
+				return false
+			}
+		}
+		return true
+	}
+	return types.Identical(want, have)
+}
+
+func unifyTuples(want, have *types.Tuple, params *types.TypeParamList, bound map[*types.TypeParam]types.Type) bool {
+	if want.Len() != have.Len() {
+		return false
+	}
+	for i := 0; i < want.Len(); i++ {
+		if !unify(want.At(i).Type(), have.At(i).Type(), params, bound) {
+			return false
+		}
+	}
+	return true
+}
+
+func isParamOf(param *types.TypeParam, params *types.TypeParamList) bool {
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i) == param {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MockStyles are the styles GenerateMock supports: minimal mocks, with a
+// function field per method that the method calls when set, and testify
+// mocks, recording calls with github.com/stretchr/testify/mock.
+var MockStyles = []string{"minimal", "testify"}
+
+// LookupInterface returns the interface of result named name, either plain
+// or qualified by import path or its last elements, such as store.Store. It
+// is an error for a plain name to match interfaces of several packages.
+func LookupInterface(result *Result, name string) (InterfaceInfo, error) {
+	return lookupDeclaration(result.Interfaces, "interface", name, func(iface InterfaceInfo) (string, string) {
+		return iface.Package, iface.Name
+	})
+}
+
+// lookupDeclaration returns the one of decls of kind named name, either
+// plain or qualified by import path or its last elements.
+func lookupDeclaration[T any](decls []T, kind, name string, names func(T) (pkg, name string)) (T, error) {
+	var matches []string
+	var found T
+	for _, decl := range decls {
+		pkg, declName := names(decl)
+		if MatchesName(pkg, declName, name) {
+			matches = append(matches, pkg+"."+declName)
+			found = decl
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return found, fmt.Errorf("no %s %s found", kind, name)
+	case 1:
+		return found, nil
+	}
+	var zero T
+	sort.Strings(matches)
+	return zero, fmt.Errorf("%s %s is ambiguous: qualify it as one of %s", kind, name, strings.Join(matches, ", "))
+}
+
+// MatchesName reports whether the declaration declName of package pkg is
+// the one name stands for, either plain or qualified by import path or its
+// last elements.
+func MatchesName(pkg, declName, name string) bool {
+	qualified := pkg + "." + declName
+	return declName == name || qualified == name || strings.HasSuffix(qualified, "/"+name)
+}
+
+// GenerateMock returns the formatted source of a file of package pkgName
+// declaring Mock<Name>, a mock of iface in style. The interface must have
+// been analyzed with Options.StructuredTypes. Its methods must be exported,
+// and anonymous struct and interface types in their signatures must be
+// empty, as only their kind is known. So must the constraints of a generic
+// interface: named constraints and any are supported, unions such as
+// ~int | ~string are not.
+func GenerateMock(iface InterfaceInfo, pkgName, style string) ([]byte, error) {
+	if !token.IsIdentifier(pkgName) {
+		return nil, fmt.Errorf("invalid package name %q", pkgName)
+	}
+	g := newCodeGenerator()
+	switch style {
+	case "minimal":
+	case "testify":
+		g.importAs("github.com/stretchr/testify/mock", "mock")
+	default:
+		return nil, fmt.Errorf("unknown mock style %q", style)
+	}
+
+	mock := "Mock" + iface.Name
+	typeParams, typeArgs := "", ""
+	if len(iface.TypeParams) > 0 {
+		params := make([]string, len(iface.TypeParams))
+		args := make([]string, len(iface.TypeParams))
+		for i, param := range iface.TypeParams {
+			constraint, err := g.constraint(param)
+			if err != nil {
+				return nil, fmt.Errorf("%s: type parameter %s: %w", iface.Name, param.Name, err)
+			}
+			params[i] = param.Name + " " + constraint
+			args[i] = param.Name
+		}
+		typeParams = "[" + strings.Join(params, ", ") + "]"
+		typeArgs = "[" + strings.Join(args, ", ") + "]"
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %s is a mock of %s.%s.\n", mock, iface.Package, iface.Name)
+	fmt.Fprintf(&body, "type %s%s struct {\n", mock, typeParams)
+	methods := make([]mockMethod, 0, len(iface.Methods))
+	for _, method := range iface.Methods {
+		m, err := g.method(method)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", iface.Name, method.Name, err)
+		}
+		methods = append(methods, m)
+	}
+	if style == "testify" {
+		body.WriteString("mock.Mock\n")
+	} else {
+		for _, m := range methods {
+			fmt.Fprintf(&body, "%sFunc func(%s) %s\n", m.name, strings.Join(m.paramTypes, ", "), m.results())
+		}
+	}
+	body.WriteString("}\n\n")
+	// Generic mocks can only be checked against the interface once
+	// instantiated.
+	if typeArgs == "" {
+		fmt.Fprintf(&body, "var _ %s = (*%s)(nil)\n", g.qualified(iface.Package, iface.Name), mock)
+	}
+
+	for _, m := range methods {
+		fmt.Fprintf(&body, "\nfunc (m *%s%s) %s(%s) (%s) {\n", mock, typeArgs, m.name, m.signatureParams(), m.namedResults())
+		if style == "testify" {
+			m.writeTestify(&body)
+		} else {
+			m.writeMinimal(&body)
+		}
+		body.WriteString("}\n")
+	}
+
+	return g.source("Code generated by goanalyzer genmock; DO NOT EDIT.", pkgName, body.Bytes())
+}
+
+// mockMethod is a method of a mock, with its parameters and results named
+// apart from each other and from the package names imported.
+type mockMethod struct {
+	name        string
+	params      []string
+	paramTypes  []string
+	variadic    bool
+	resultNames []string
+	resultTypes []string
+}
+
+func (m mockMethod) signatureParams() string {
+	params := make([]string, len(m.params))
+	for i, name := range m.params {
+		params[i] = name + " " + m.paramTypes[i]
+	}
+	return strings.Join(params, ", ")
+}
+
+func (m mockMethod) results() string {
+	switch len(m.resultTypes) {
+	case 0:
+		return ""
+	case 1:
+		return m.resultTypes[0]
+	}
+	return "(" + strings.Join(m.resultTypes, ", ") + ")"
+}
+
+func (m mockMethod) namedResults() string {
+	results := make([]string, len(m.resultNames))
+	for i, name := range m.resultNames {
+		results[i] = name + " " + m.resultTypes[i]
+	}
+	return strings.Join(results, ", ")
+}
+
+// arguments passes the parameters on, spreading a variadic one.
+func (m mockMethod) arguments() string {
+	args := strings.Join(m.params, ", ")
+	if m.variadic {
+		args += "..."
+	}
+	return args
+}
+
+// writeMinimal calls the method's function field when set, and otherwise
+// returns zero values.
+func (m mockMethod) writeMinimal(w *bytes.Buffer) {
+	if len(m.resultNames) == 0 {
+		fmt.Fprintf(w, "if m.%sFunc != nil {\nm.%[1]sFunc(%s)\n}\n", m.name, m.arguments())
+		return
+	}
+	fmt.Fprintf(w, "if m.%sFunc != nil {\nreturn m.%[1]sFunc(%s)\n}\nreturn\n", m.name, m.arguments())
+}
+
+// writeTestify records the call and returns the values set up for it,
+// leaving nil ones zero.
+func (m mockMethod) writeTestify(w *bytes.Buffer) {
+	if len(m.resultNames) == 0 {
+		fmt.Fprintf(w, "m.Called(%s)\n", strings.Join(m.params, ", "))
+		return
+	}
+	fmt.Fprintf(w, "ret := m.Called(%s)\n", strings.Join(m.params, ", "))
+	for i, name := range m.resultNames {
+		fmt.Fprintf(w, "if v := ret.Get(%d); v != nil {\n%s = v.(%s)\n}\n", i, name, m.resultTypes[i])
+	}
+	w.WriteString("return\n")
+}
+
+// constraint renders the constraint of param. Its TypeRef gives only the
+// kind of an anonymous interface, so only an empty one is rendered.
+func (g *codeGenerator) constraint(param TypeParam) (string, error) {
+	if param.ConstraintRef == nil {
+		return "", fmt.Errorf("no structured types; analyze with StructuredTypes")
+	}
+	if param.ConstraintRef.Kind == "interface" && param.Constraint != "interface{}" {
+		return "", fmt.Errorf("constraint %s is not supported", param.Constraint)
+	}
+	return g.topLevelType(param.ConstraintRef, param.Constraint)
+}
+
+func (g *codeGenerator) method(method MethodInfo) (mockMethod, error) {
+	if !token.IsExported(method.Name) {
+		return mockMethod{}, fmt.Errorf("unexported methods can only be implemented in the interface's own package")
+	}
+	if len(method.ReturnTypeRefs) != len(method.ReturnTypes) {
+		return mockMethod{}, fmt.Errorf("no structured types; analyze with StructuredTypes")
+	}
+	m := mockMethod{name: method.Name, variadic: method.Variadic}
+	for i, param := range method.Parameters {
+		if param.TypeRef == nil {
+			return mockMethod{}, fmt.Errorf("no structured types; analyze with StructuredTypes")
+		}
+		typ, err := g.topLevelType(param.TypeRef, param.Type)
+		if err != nil {
+			return mockMethod{}, err
+		}
+		if m.variadic && i == len(method.Parameters)-1 {
+			typ = "..." + strings.TrimPrefix(typ, "[]")
+		}
+		m.paramTypes = append(m.paramTypes, typ)
+	}
+	for i, ref := range method.ReturnTypeRefs {
+		typ, err := g.topLevelType(ref, method.ReturnTypes[i])
+		if err != nil {
+			return mockMethod{}, err
+		}
+		m.resultTypes = append(m.resultTypes, typ)
+	}
+
+	// Parameter names are kept where they cannot clash with the receiver,
+	// the results or the imports.
+	taken := map[string]bool{"m": true, "ret": true, "v": true}
+	for alias := range g.aliases {
+		taken[alias] = true
+	}
+	m.params = make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		if name := param.Name; name != "" && name != "_" && !taken[name] {
+			taken[name] = true
+			m.params[i] = name
+		}
+	}
+	for i := range m.params {
+		if m.params[i] != "" {
+			continue
+		}
+		name := "arg" + strconv.Itoa(i)
+		for taken[name] {
+			name += "_"
+		}
+		taken[name] = true
+		m.params[i] = name
+	}
+	for i := range m.resultTypes {
+		name := "r" + strconv.Itoa(i)
+		for taken[name] {
+			name += "_"
+		}
+		taken[name] = true
+		m.resultNames = append(m.resultNames, name)
+	}
+	return m, nil
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// mockSubject is a module with interfaces whose mocks need their parameters
+// renamed, or their constraints qualified and imported.
+var mockSubject = map[string]string{
+	"go.mod": "module example.com/store\n\ngo 1.22\n",
+	"store.go": `package store
+
+import "fmt"
+
+type Store interface {
+	Put(arg1 string, _ int) error
+	Get(_ string, arg0 int) (string, error)
+	Log(format string, args ...any)
+	Describe(fmt fmt.Stringer) string
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+type Sum[T Number] interface {
+	Add(v T) T
+}
+
+type Cache[K comparable, V fmt.Stringer] interface {
+	Get(key K) (V, bool)
+}
+`,
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// TestGenerateMockCompiles type-checks the minimal mock of every interface
+// of mockSubject.
+func TestGenerateMockCompiles(t *testing.T) {
+	dir := writeModule(t, mockSubject)
+	result, err := New(func(opts *Options) {
+		opts.TypeStyle = typeStyleRelative
+		opts.StructuredTypes = true
+	}).Analyze(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := make(map[string]*types.Package)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		imported[pkg.PkgPath] = pkg.Types
+	})
+	importer := importerFunc(func(path string) (*types.Package, error) {
+		if pkg, ok := imported[path]; ok {
+			return pkg, nil
+		}
+		return nil, os.ErrNotExist
+	})
+
+	for _, iface := range result.Interfaces {
+		t.Run(iface.Name, func(t *testing.T) {
+			src, err := GenerateMock(iface, "mocks", "minimal")
+			if err != nil {
+				t.Fatal(err)
+			}
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "mock.go", src, 0)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, src)
+			}
+			conf := types.Config{Importer: importer}
+			if _, err := conf.Check("example.com/store/mocks", fset, []*ast.File{file}, nil); err != nil {
+				t.Fatalf("%v\n%s", err, src)
+			}
+		})
+	}
+}
+
+func TestGenerateMockUnsupportedConstraint(t *testing.T) {
+	iface := InterfaceInfo{
+		Name:    "Sum",
+		Package: "example.com/store",
+		TypeParams: []TypeParam{{
+			Name:          "T",
+			Constraint:    "~int | ~float64",
+			ConstraintRef: &TypeRef{Kind: "interface"},
+		}},
+	}
+	if _, err := GenerateMock(iface, "mocks", "minimal"); err == nil {
+		t.Error("GenerateMock succeeded for a union constraint")
+	}
+}
@@ -0,0 +1,24 @@
+package analyzer
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// collectGoroutineLaunches locates the go statements in every function and
+// method body, keyed by the position of the declaring function's name.
+func collectGoroutineLaunches(pkgs []*packages.Package) map[Position][]Position {
+	launches := make(map[Position][]Position)
+	forEachFuncBody(pkgs, func(pkg *packages.Package, key Position, decl *ast.FuncDecl) {
+		sites := make([]Position, 0)
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if stmt, ok := n.(*ast.GoStmt); ok {
+				sites = append(sites, newPosition(pkg.Fset, stmt.Pos(), stmt.End()))
+			}
+			return true
+		})
+		launches[key] = sites
+	})
+	return launches
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import "go/types"
+
+type InterfaceEdge struct {
+	From Declaration `json:"from"`
+	To   Declaration `json:"to"`
+	// Embedded is set when From embeds To explicitly rather than merely
+	// declaring a superset of its methods.
+	Embedded bool `json:"embedded"`
+}
+
+// buildInterfaceHierarchy returns an edge from every interface to each other
+// interface it is assignable to, i.e. whose method set it contains.
+func buildInterfaceHierarchy(interfaces []InterfaceInfo, ifaceTypes []*types.Interface) []InterfaceEdge {
+	edges := make([]InterfaceEdge, 0)
+	for i, from := range ifaceTypes {
+		for j, to := range ifaceTypes {
+			if i == j || !types.Implements(from, to) {
+				continue
+			}
+
+			embedded := false
+			for k := 0; k < from.NumEmbeddeds(); k++ {
+				if from.EmbeddedType(k).Underlying() == to {
+					embedded = true
+					break
+				}
+			}
+
+			edges = append(edges, InterfaceEdge{
+				From:     interfaceDeclaration(interfaces[i]),
+				To:       interfaceDeclaration(interfaces[j]),
+				Embedded: embedded,
+			})
+		}
+	}
+	return edges
+}
+
+func interfaceDeclaration(iface InterfaceInfo) Declaration {
+	return Declaration{
+		Name:     iface.Name,
+		Package:  iface.Package,
+		Position: iface.Position,
+	}
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import "go/types"
+
+// embeddingDepth returns how many levels of embedded structs t holds: 0 for
+// a struct without embedded structs, 1 for one embedding only such structs,
+// and so on. Types already on the path are not entered again, so pointer
+// embeddings referring back to an outer struct terminate.
+func embeddingDepth(t types.Type) int {
+	var depth func(t types.Type, path map[types.Type]bool) int
+	depth = func(t types.Type, path map[types.Type]bool) int {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		strct, ok := t.Underlying().(*types.Struct)
+		if !ok || path[t] {
+			return 0
+		}
+		path[t] = true
+		defer delete(path, t)
+
+		deepest := 0
+		for i := 0; i < strct.NumFields(); i++ {
+			field := strct.Field(i)
+			if !field.Anonymous() {
+				continue
+			}
+			embedded := field.Type()
+			if ptr, ok := embedded.(*types.Pointer); ok {
+				embedded = ptr.Elem()
+			}
+			if _, ok := embedded.Underlying().(*types.Struct); ok && !path[embedded] {
+				deepest = max(deepest, 1+depth(embedded, path))
+			}
+		}
+		return deepest
+	}
+	return depth(t, make(map[types.Type]bool))
+}
+
+// complexityRankings orders the types of result by the parameters of their
+// declared methods, by embedding depth and by field count. Types scoring 0
+// are left out.
+func complexityRankings(result AnalysisResult, top int) (params, embedding, fields []RankedDeclaration) {
+	params = make([]RankedDeclaration, 0)
+	embedding = make([]RankedDeclaration, 0)
+	fields = make([]RankedDeclaration, 0)
+
+	paramTotal := func(methods []MethodInfo) int {
+		total := 0
+		for _, method := range methods {
+			if !method.Promoted {
+				total += method.ParamCount
+			}
+		}
+		return total
+	}
+	add := func(ranking []RankedDeclaration, decl Declaration, count int) []RankedDeclaration {
+		if count == 0 {
+			return ranking
+		}
+		return append(ranking, RankedDeclaration{Declaration: decl, Count: count})
+	}
+
+	for _, iface := range result.Interfaces {
+		params = add(params, interfaceDeclaration(iface), paramTotal(iface.Methods))
+	}
+	for _, strct := range result.Structs {
+		decl := Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position}
+		params = add(params, decl, paramTotal(strct.Methods))
+		embedding = add(embedding, decl, strct.EmbeddingDepth)
+		fields = add(fields, decl, strct.FieldCount)
+	}
+
+	return rank(params, top), rank(embedding, top), rank(fields, top)
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+)
+
+// Declarations carry an ID that stays the same as long as the declaration
+// keeps its name and parameter types, wherever it moves:
+//
+//	example.com/app/store.Store                              a type
+//	example.com/app/store.Store.Get(context.Context,string)  a method
+//	example.com/app/store.Store.cache                        a field
+//	example.com/app/store.Index[string,int]                  an instantiation
+//
+// Types in IDs are always qualified by their full import path, whatever
+// -type-style says, so that IDs from different runs compare equal.
+
+// typeID identifies a package-level type or function by name.
+func typeID(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// methodID identifies fn as a method of the type identified by owner,
+// including its parameter types.
+func methodID(owner string, fn *types.Func) string {
+	signature := fn.Type().(*types.Signature)
+	params := make([]string, signature.Params().Len())
+	for i := range params {
+		t := signature.Params().At(i).Type()
+		if signature.Variadic() && i == len(params)-1 {
+			params[i] = "..." + types.TypeString(t.(*types.Slice).Elem(), idQualifier)
+			continue
+		}
+		params[i] = types.TypeString(t, idQualifier)
+	}
+	return owner + "." + fn.Name() + "(" + strings.Join(params, ",") + ")"
+}
+
+// functionID identifies a function, or a method by its receiver type.
+func functionID(fn *types.Func) string {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return methodID(fn.Pkg().Path(), fn)
+	}
+	return methodID(fn.Pkg().Path()+"."+receiverName(fn), fn)
+}
+
+// instanceID identifies the instantiation of obj with args.
+func instanceID(obj types.Object, args *types.TypeList) string {
+	names := make([]string, args.Len())
+	for i := range names {
+		names[i] = types.TypeString(args.At(i), idQualifier)
+	}
+	return typeID(obj) + "[" + strings.Join(names, ",") + "]"
+}
+
+// canonicalTypes renders the parameter and result types of signature as
+// "(T1,T2)(R1,R2)", qualified like IDs, for comparing signatures across
+// runs. A variadic parameter is written "...T", as in methodID.
+func canonicalTypes(signature *types.Signature) string {
+	tuple := func(t *types.Tuple, variadic bool) string {
+		names := make([]string, t.Len())
+		for i := range names {
+			typ := t.At(i).Type()
+			if variadic && i == len(names)-1 {
+				names[i] = "..." + types.TypeString(typ.(*types.Slice).Elem(), idQualifier)
+				continue
+			}
+			names[i] = types.TypeString(typ, idQualifier)
+		}
+		return "(" + strings.Join(names, ",") + ")"
+	}
+	return tuple(signature.Params(), signature.Variadic()) + tuple(signature.Results(), false)
+}
+
+func idQualifier(pkg *types.Package) string {
+	return pkg.Path()
+}
+
+// rebaseIDs replaces the prefix *id of the IDs of a type's methods and
+// fields with to, then *id itself.
+func rebaseIDs(id *string, to string, methods []MethodInfo, fields ...[]FieldInfo) {
+	for i := range methods {
+		methods[i].ID = to + strings.TrimPrefix(methods[i].ID, *id)
+	}
+	for _, list := range fields {
+		for i := range list {
+			list[i].ID = to + strings.TrimPrefix(list[i].ID, *id)
+		}
+	}
+	*id = to
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Implementers returns the structs of result implementing the interface
+// name, either plain or qualified by import path or its last elements, such
+// as store.Store, ordered by struct. It is an error for result to know no
+// such interface, or for a plain name to match interfaces of several
+// packages.
+func Implementers(result *Result, name string) ([]Implementation, error) {
+	known := make([]Declaration, 0, len(result.Interfaces)+len(result.ExternalInterfaces))
+	for _, iface := range result.Interfaces {
+		known = append(known, interfaceDeclaration(iface))
+	}
+	for _, iface := range result.ExternalInterfaces {
+		known = append(known, interfaceDeclaration(iface))
+	}
+	target, err := lookupDeclaration(known, "interface", name, func(decl Declaration) (string, string) {
+		return decl.Package, decl.Name
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	impls := make([]Implementation, 0)
+	for _, strct := range result.Structs {
+		for _, iface := range strct.ImplementedInterfaces {
+			if iface.Package == target.Package && iface.Name == target.Name {
+				impls = append(impls, Implementation{
+					Struct:    Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position},
+					Interface: iface,
+				})
+			}
+		}
+	}
+	sortImplementations(impls)
+	return impls, nil
+}
+
+// ImplementersFormats are the formats WriteImplementers supports.
+var ImplementersFormats = []string{"text", "json"}
+
+// WriteImplementers writes impls to w as JSON or as text, one line per
+// struct. The text names the receiver implementing the interface, which is
+// a pointer when some of the methods have pointer receivers:
+//
+//	example.com/app/pg.Store @ pg/store.go:12: *Store implements example.com/app/store.Store
+//	example.com/app/mem.Store @ mem/store.go:8: Store implements example.com/app/store.Store
+func WriteImplementers(w io.Writer, impls []Implementation, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(w, impls)
+	case "text":
+	default:
+		return fmt.Errorf("unknown implementers format %q", format)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, impl := range impls {
+		receiver := impl.Struct.Name
+		if impl.Interface.ReceiverKind != receiverValue {
+			receiver = "*" + receiver
+		}
+		iface := impl.Interface.Name
+		if impl.Interface.Package != "" {
+			iface = impl.Interface.Package + "." + iface
+		}
+		if len(impl.Interface.TypeArguments) > 0 {
+			iface += "[" + strings.Join(impl.Interface.TypeArguments, ", ") + "]"
+		}
+		fmt.Fprintf(bw, "%s.%s @ %s:%d: %s implements %s\n", impl.Struct.Package, impl.Struct.Name,
+			impl.Struct.Position.Path, impl.Struct.Position.Line, receiver, iface)
+	}
+	return bw.Flush()
+}
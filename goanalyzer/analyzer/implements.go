@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// indexedInterface is an interface declared in one of the analyzed packages.
+type indexedInterface struct {
+	info  InterfaceInfo
+	iface *types.Interface
+	// generic is set for generic interfaces, which are only implemented
+	// once instantiated.
+	generic *types.Named
+}
+
+// buildInterfaceIndex collects the interfaces with methods declared in the
+// analyzed packages up front, so that each struct is checked against the
+// interfaces of every package, including those processed after its own.
+func buildInterfaceIndex(pkgs []*packages.Package, analyzed func(*packages.Package) bool, skip func(*packages.Package, types.Object) bool, src *sourceIndex, style typeStyle) []indexedInterface {
+	index := make([]indexedInterface, 0)
+	for _, pkg := range pkgs {
+		if !analyzed(pkg) {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if obj == nil || skip(pkg, obj) {
+				continue
+			}
+			if _, ok := obj.(*types.TypeName); !ok {
+				continue
+			}
+			iface, ok := obj.Type().Underlying().(*types.Interface)
+			if !ok || iface.NumMethods() == 0 {
+				continue
+			}
+			if info := processInterface(obj, pkg, src, style); info != nil {
+				indexed := indexedInterface{info: *info, iface: iface}
+				if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+					indexed.generic = named
+				}
+				index = append(index, indexed)
+			}
+		}
+	}
+	return index
+}
+
+// implementsDeclared reports whether strct already lists iface among its
+// implemented interfaces.
+func implementsDeclared(strct *StructInfo, iface InterfaceInfo) bool {
+	for _, implemented := range strct.ImplementedInterfaces {
+		if implemented.Package == iface.Package && implemented.Name == iface.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Receiver kinds tell which receivers a struct's methods implementing an
+// interface have: "value" when a plain value of the struct implements it,
+// "pointer" when every method needs a pointer, and "both" when some do, so
+// that only a pointer to the struct implements it either.
+const (
+	receiverValue   = "value"
+	receiverPointer = "pointer"
+	receiverBoth    = "both"
+)
+
+// receiverKind returns the receiver kind with which t, or a pointer to it,
+// implements iface. Methods in t's own method set, including those promoted
+// through embedded pointers, count as value methods.
+func receiverKind(t types.Type, iface *types.Interface, cache *typeCache) string {
+	values := cache.methodSet(t)
+	byValue := 0
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if values.Lookup(method.Pkg(), method.Name()) != nil {
+			byValue++
+		}
+	}
+	switch byValue {
+	case iface.NumMethods():
+		return receiverValue
+	case 0:
+		return receiverPointer
+	default:
+		return receiverBoth
+	}
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+type MethodIndexEntry struct {
+	Kind     string   `json:"kind"`
+	Type     string   `json:"type"`
+	Package  string   `json:"package"`
+	Position Position `json:"position"`
+}
+
+// MethodIndex is the output of -format index: every method name mapped to
+// the interfaces and structs declaring it. Promoted methods are left out,
+// since they are listed under the type that declares them.
+type MethodIndex struct {
+	SchemaVersion    string                        `json:"schemaVersion"`
+	GeneratorVersion string                        `json:"generatorVersion"`
+	Methods          map[string][]MethodIndexEntry `json:"methods"`
+}
+
+func buildMethodIndex(result AnalysisResult) MethodIndex {
+	index := MethodIndex{
+		SchemaVersion:    result.SchemaVersion,
+		GeneratorVersion: result.GeneratorVersion,
+		Methods:          make(map[string][]MethodIndexEntry),
+	}
+
+	add := func(kind, typeName, pkg string, methods []MethodInfo) {
+		for _, method := range methods {
+			if method.Promoted {
+				continue
+			}
+			index.Methods[method.Name] = append(index.Methods[method.Name], MethodIndexEntry{
+				Kind:     kind,
+				Type:     typeName,
+				Package:  pkg,
+				Position: method.Position,
+			})
+		}
+	}
+
+	for _, iface := range result.Interfaces {
+		add("interface", iface.Name, iface.Package, iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		add("struct", strct.Name, strct.Package, strct.Methods)
+	}
+
+	return index
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Instantiation lists the distinct type arguments a generic type or function
+// is instantiated with.
+type Instantiation struct {
+	Generic   Declaration `json:"generic"`
+	Instances []Instance  `json:"instances"`
+}
+
+// Instance is one list of type arguments, with the packages using it.
+type Instance struct {
+	ID            string   `json:"id"`
+	TypeArguments []string `json:"typeArguments"`
+	Packages      []string `json:"packages"`
+}
+
+// instantiations accumulates the instantiations found across packages,
+// keyed by the generic declaration and then by the type arguments.
+type instantiations map[types.Object]map[string]*Instance
+
+// collectInstantiations adds the instantiations occurring in pkg. Generic
+// declarations from any package count, including the standard library.
+// Instantiations inside generic code, with type parameters as arguments, are
+// left out: the concrete arguments show up where that code is instantiated.
+func collectInstantiations(pkg *packages.Package, into instantiations, style typeStyle, skipFile func(filename string) bool) {
+	for ident, inst := range pkg.TypesInfo.Instances {
+		if skipFile(pkg.Fset.Position(ident.Pos()).Filename) {
+			continue
+		}
+		obj := pkg.TypesInfo.Uses[ident]
+		if obj == nil || obj.Pkg() == nil {
+			continue
+		}
+
+		args := make([]string, inst.TypeArgs.Len())
+		generic := false
+		for i := range args {
+			arg := inst.TypeArgs.At(i)
+			if containsTypeParam(arg) {
+				generic = true
+				break
+			}
+			args[i] = style.typeString(arg, obj.Pkg())
+		}
+		if generic {
+			continue
+		}
+
+		byArgs, ok := into[obj]
+		if !ok {
+			byArgs = make(map[string]*Instance)
+			into[obj] = byArgs
+		}
+		key := strings.Join(args, ", ")
+		instance, ok := byArgs[key]
+		if !ok {
+			instance = &Instance{ID: instanceID(obj, inst.TypeArgs), TypeArguments: args}
+			byArgs[key] = instance
+		}
+		if !slices.Contains(instance.Packages, pkg.PkgPath) {
+			instance.Packages = append(instance.Packages, pkg.PkgPath)
+		}
+	}
+}
+
+// list returns the instantiations ordered by generic declaration, with
+// instances and packages sorted.
+func (into instantiations) list(fset *token.FileSet) []Instantiation {
+	list := make([]Instantiation, 0, len(into))
+	for obj, byArgs := range into {
+		inst := Instantiation{
+			Generic: Declaration{
+				Name:     obj.Name(),
+				Package:  obj.Pkg().Path(),
+				Position: newPosition(fset, obj.Pos(), token.NoPos),
+			},
+			Instances: make([]Instance, 0, len(byArgs)),
+		}
+		for _, instance := range byArgs {
+			sort.Strings(instance.Packages)
+			inst.Instances = append(inst.Instances, *instance)
+		}
+		sort.Slice(inst.Instances, func(i, j int) bool {
+			return strings.Join(inst.Instances[i].TypeArguments, ", ") < strings.Join(inst.Instances[j].TypeArguments, ", ")
+		})
+		list = append(list, inst)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].Generic, list[j].Generic
+		return a.Package+"."+a.Name < b.Package+"."+b.Name
+	})
+	return list
+}
+
+// containsTypeParam reports whether t mentions a type parameter.
+func containsTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Named:
+		if args := t.TypeArgs(); args != nil {
+			for i := 0; i < args.Len(); i++ {
+				if containsTypeParam(args.At(i)) {
+					return true
+				}
+			}
+		}
+	case *types.Pointer:
+		return containsTypeParam(t.Elem())
+	case *types.Slice:
+		return containsTypeParam(t.Elem())
+	case *types.Array:
+		return containsTypeParam(t.Elem())
+	case *types.Chan:
+		return containsTypeParam(t.Elem())
+	case *types.Map:
+		return containsTypeParam(t.Key()) || containsTypeParam(t.Elem())
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if containsTypeParam(tuple.At(i).Type()) {
+					return true
+				}
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if containsTypeParam(t.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
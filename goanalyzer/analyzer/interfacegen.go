@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// LookupStruct returns the struct of result named name, either plain or
+// qualified by import path or its last elements, such as pg.Store. It is an
+// error for a plain name to match structs of several packages.
+func LookupStruct(result *Result, name string) (StructInfo, error) {
+	return lookupDeclaration(result.Structs, "struct", name, func(strct StructInfo) (string, string) {
+		return strct.Package, strct.Name
+	})
+}
+
+// GenerateInterface returns the formatted source of a file of package
+// pkgName declaring interface name, with the exported methods of strct,
+// promoted ones included, that filter matches, or all of them when filter
+// is nil. The struct must have been analyzed with Options.StructuredTypes,
+// and its type parameters become those of the interface. The file is not
+// marked as generated: it is meant to be kept and edited, and analyzed
+// like the rest of the code.
+func GenerateInterface(strct StructInfo, name, pkgName string, filter *regexp.Regexp) ([]byte, error) {
+	if !token.IsIdentifier(pkgName) {
+		return nil, fmt.Errorf("invalid package name %q", pkgName)
+	}
+	if !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("invalid interface name %q", name)
+	}
+	g := newCodeGenerator()
+
+	typeParams := ""
+	if len(strct.TypeParams) > 0 {
+		params := make([]string, len(strct.TypeParams))
+		for i, param := range strct.TypeParams {
+			params[i] = param.Name + " " + param.Constraint
+		}
+		typeParams = "[" + strings.Join(params, ", ") + "]"
+	}
+
+	var methods bytes.Buffer
+	for _, method := range strct.Methods {
+		if !token.IsExported(method.Name) || filter != nil && !filter.MatchString(method.Name) {
+			continue
+		}
+		signature, err := g.signature(method)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", strct.Name, method.Name, err)
+		}
+		if methods.Len() > 0 && method.Doc != "" {
+			methods.WriteString("\n")
+		}
+		writeComment(&methods, method.Doc)
+		fmt.Fprintf(&methods, "%s%s\n", method.Name, signature)
+	}
+	if methods.Len() == 0 {
+		if filter != nil {
+			return nil, fmt.Errorf("no exported method of %s matches %s", strct.Name, filter)
+		}
+		return nil, fmt.Errorf("%s has no exported methods", strct.Name)
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %s is implemented by %s.%s.\n", name, strct.Package, strct.Name)
+	fmt.Fprintf(&body, "type %s%s interface {\n", name, typeParams)
+	body.Write(methods.Bytes())
+	body.WriteString("}\n")
+	header := fmt.Sprintf("Extracted from %s.%s by goanalyzer extract-interface.", strct.Package, strct.Name)
+	return g.source(header, pkgName, body.Bytes())
+}
+
+// signature renders the parameters and results of method, keeping their
+// names.
+func (g *codeGenerator) signature(method MethodInfo) (string, error) {
+	if len(method.ReturnTypeRefs) != len(method.ReturnTypes) {
+		return "", fmt.Errorf("no structured types; analyze with StructuredTypes")
+	}
+	params := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		if param.TypeRef == nil {
+			return "", fmt.Errorf("no structured types; analyze with StructuredTypes")
+		}
+		typ, err := g.topLevelType(param.TypeRef, param.Type)
+		if err != nil {
+			return "", err
+		}
+		if method.Variadic && i == len(method.Parameters)-1 {
+			typ = "..." + strings.TrimPrefix(typ, "[]")
+		}
+		params[i] = strings.TrimSpace(param.Name + " " + typ)
+	}
+	results := make([]string, len(method.ReturnTypeRefs))
+	for i, ref := range method.ReturnTypeRefs {
+		typ, err := g.topLevelType(ref, method.ReturnTypes[i])
+		if err != nil {
+			return "", err
+		}
+		results[i] = typ
+	}
+
+	signature := "(" + strings.Join(params, ", ") + ")"
+	switch len(results) {
+	case 0:
+	case 1:
+		signature += " " + results[0]
+	default:
+		signature += " (" + strings.Join(results, ", ") + ")"
+	}
+	return signature, nil
+}
+
+// writeComment writes doc as a line comment, if any.
+func writeComment(w *bytes.Buffer, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(doc, "\n"), "\n") {
+		w.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type LeakyAPI struct {
+	Declaration Declaration `json:"declaration"`
+	Type        string      `json:"type"`
+}
+
+func findLeakyAPIs(pkg *packages.Package, style typeStyle, skip func(types.Object) bool) []LeakyAPI {
+	leaks := make([]LeakyAPI, 0)
+
+	checkSignature := func(name string, fn *types.Func) {
+		signature, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return
+		}
+
+		decl := Declaration{
+			Name:     name,
+			Package:  pkg.PkgPath,
+			Position: newPosition(pkg.Fset, fn.Pos(), token.NoPos),
+		}
+
+		// Report each offending type once per declaration
+		seen := make(map[string]bool)
+		for _, tuple := range []*types.Tuple{signature.Params(), signature.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				for _, leaked := range leakedTypes(tuple.At(i).Type(), pkg.Types) {
+					typeName := style.typeString(leaked, pkg.Types)
+					if seen[typeName] {
+						continue
+					}
+					seen[typeName] = true
+					leaks = append(leaks, LeakyAPI{
+						Declaration: decl,
+						Type:        typeName,
+					})
+				}
+			}
+		}
+	}
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if obj == nil || !obj.Exported() || skip(obj) {
+			continue
+		}
+
+		switch obj := obj.(type) {
+		case *types.Func:
+			checkSignature(obj.Name(), obj)
+		case *types.TypeName:
+			named, ok := obj.Type().(*types.Named)
+			if !ok || obj.IsAlias() {
+				continue
+			}
+
+			for i := 0; i < named.NumMethods(); i++ {
+				method := named.Method(i)
+				if method.Exported() {
+					checkSignature(obj.Name()+"."+method.Name(), method)
+				}
+			}
+
+			if iface, ok := named.Underlying().(*types.Interface); ok {
+				for i := 0; i < iface.NumExplicitMethods(); i++ {
+					method := iface.ExplicitMethod(i)
+					if method.Exported() {
+						checkSignature(obj.Name()+"."+method.Name(), method)
+					}
+				}
+			}
+		}
+	}
+
+	return leaks
+}
+
+// leakedTypes returns the named types reachable from t that callers outside
+// of from cannot refer to: unexported types and types from internal packages.
+// Named types are visited once, so self- and mutually-referential types such
+// as linked lists and trees terminate.
+func leakedTypes(t types.Type, from *types.Package) []types.Type {
+	var leaked []types.Type
+	visited := make(map[*types.Named]bool)
+
+	var walk func(t types.Type)
+	walk = func(t types.Type) {
+		switch t := t.(type) {
+		case *types.Named:
+			if visited[t] {
+				return
+			}
+			visited[t] = true
+
+			obj := t.Obj()
+			if obj.Pkg() != nil {
+				if !obj.Exported() || (isInternalPackage(obj.Pkg().Path()) && !isInternalPackage(from.Path())) {
+					leaked = append(leaked, t)
+					return
+				}
+			}
+			if args := t.TypeArgs(); args != nil {
+				for i := 0; i < args.Len(); i++ {
+					walk(args.At(i))
+				}
+			}
+		case *types.Pointer:
+			walk(t.Elem())
+		case *types.Slice:
+			walk(t.Elem())
+		case *types.Array:
+			walk(t.Elem())
+		case *types.Chan:
+			walk(t.Elem())
+		case *types.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case *types.Signature:
+			for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+				for i := 0; i < tuple.Len(); i++ {
+					walk(tuple.At(i).Type())
+				}
+			}
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				if t.Field(i).Exported() {
+					walk(t.Field(i).Type())
+				}
+			}
+		case *types.Interface:
+			for i := 0; i < t.NumMethods(); i++ {
+				if t.Method(i).Exported() {
+					walk(t.Method(i).Type())
+				}
+			}
+		}
+	}
+	walk(t)
+
+	return leaked
+}
+
+func isInternalPackage(path string) bool {
+	return path == "internal" ||
+		strings.HasPrefix(path, "internal/") ||
+		strings.HasSuffix(path, "/internal") ||
+		strings.Contains(path, "/internal/")
+}
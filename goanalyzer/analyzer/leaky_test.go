@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"context"
+	"go/types"
+	"slices"
+	"testing"
+)
+
+// The models fixture has a self-referential list (Node), a tree referring
+// to itself through slices and pointers (Tree), and two mutually
+// referential types (Graph and vertex).
+
+func TestLeakedTypesRecursive(t *testing.T) {
+	models := fixturePackage(t, "models")
+	outside := types.NewPackage("example.com/app", "app")
+	tests := []struct {
+		typ, method string
+		from        *types.Package
+		want        []string
+	}{
+		{"Node", "Next", models.Types, nil},
+		{"Node", "SetNext", models.Types, nil},
+		{"Node", "Next", outside, []string{"Node"}},
+		{"Tree", "Walk", models.Types, nil},
+		{"Tree", "Walk", outside, []string{"Tree"}},
+		{"Tree", "Depth", outside, nil},
+		{"Graph", "Vertex", models.Types, []string{"vertex"}},
+		{"Graph", "Each", models.Types, []string{"vertex"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+"."+tt.method+" from "+tt.from.Path(), func(t *testing.T) {
+			named := fixtureType(t, models, tt.typ)
+			obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, models.Types, tt.method)
+			fn, ok := obj.(*types.Func)
+			if !ok {
+				t.Fatalf("no method %s.%s", tt.typ, tt.method)
+			}
+			var got []string
+			for _, leaked := range leakedTypes(fn.Type(), tt.from) {
+				got = append(got, leaked.(*types.Named).Obj().Name())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("leakedTypes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddingDepthRecursive(t *testing.T) {
+	models := fixturePackage(t, "models")
+	for _, name := range []string{"Node", "Tree", "Graph", "vertex"} {
+		if depth := embeddingDepth(fixtureType(t, models, name)); depth != 0 {
+			t.Errorf("embeddingDepth(%s) = %d, want 0", name, depth)
+		}
+	}
+}
+
+func TestAnalyzeRecursiveTypes(t *testing.T) {
+	result, err := New(func(opts *Options) {
+		opts.TypeStyle = typeStyleRelative
+	}).Analyze(context.Background(), fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		strct, field, typ string
+	}{
+		{"Node", "next", "*Node"},
+		{"Tree", "Children", "[]*Tree"},
+		{"Tree", "Parent", "*Tree"},
+		{"Graph", "Vertices", "map[string]*vertex"},
+		{"vertex", "Graph", "*Graph"},
+		{"vertex", "Edges", "[]*vertex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strct+"."+tt.field, func(t *testing.T) {
+			strct := fixtureStruct(t, result, "go-analyzer-test/internal/models", tt.strct)
+			i := slices.IndexFunc(strct.Fields, func(field FieldInfo) bool { return field.Name == tt.field })
+			if i < 0 {
+				t.Fatalf("no field %s", tt.field)
+			}
+			if got := strct.Fields[i].Type; got != tt.typ {
+				t.Errorf("type = %q, want %q", got, tt.typ)
+			}
+		})
+	}
+
+	var leaks []string
+	for _, leak := range result.LeakyAPIs {
+		if leak.Declaration.Package == "go-analyzer-test/internal/models" {
+			leaks = append(leaks, leak.Declaration.Name+" "+leak.Type)
+		}
+	}
+	for _, want := range []string{"Graph.Vertex vertex", "Graph.Each vertex"} {
+		if !slices.Contains(leaks, want) {
+			t.Errorf("leaky APIs %v lack %q", leaks, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LocalType is a struct or interface type declared inside a function body.
+// Exactly one of Interface and Struct is set.
+type LocalType struct {
+	Function  Declaration    `json:"function"`
+	Interface *InterfaceInfo `json:"interface,omitempty"`
+	Struct    *StructInfo    `json:"struct,omitempty"`
+}
+
+// findLocalTypes returns the struct and interface types declared in the
+// bodies of pkg's functions and methods, including inside function literals
+// there, annotated with the enclosing function. Local types declared in
+// function literals at package level are not reported.
+func findLocalTypes(pkg *packages.Package, interfaces []indexedInterface, src *sourceIndex, cache *typeCache, style typeStyle, skipFile func(filename string) bool) []LocalType {
+	locals := make([]LocalType, 0)
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			fn, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			function := functionDeclaration(fn, pkg.Fset)
+
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				obj, ok := pkg.TypesInfo.Defs[spec.Name].(*types.TypeName)
+				if !ok || obj.IsAlias() {
+					return true
+				}
+				local := LocalType{Function: function}
+				// Local types are identified within their function.
+				id := functionID(fn) + "." + obj.Name()
+				switch obj.Type().Underlying().(type) {
+				case *types.Interface:
+					local.Interface = processInterface(obj, pkg, src, style)
+					if local.Interface != nil {
+						rebaseIDs(&local.Interface.ID, id, local.Interface.Methods)
+					}
+				case *types.Struct:
+					local.Struct = processStruct(obj, pkg, interfaces, src, cache, style)
+					if local.Struct != nil {
+						rebaseIDs(&local.Struct.ID, id, local.Struct.Methods, local.Struct.FunctionFields, local.Struct.Fields)
+					}
+				}
+				if local.Interface != nil || local.Struct != nil {
+					locals = append(locals, local)
+				}
+				return true
+			})
+		}
+	}
+	return locals
+}
+
+// functionDeclaration names a function, or a method as "Type.Method".
+func functionDeclaration(fn *types.Func, fset *token.FileSet) Declaration {
+	name := fn.Name()
+	if fn.Type().(*types.Signature).Recv() != nil {
+		name = receiverName(fn) + "." + name
+	}
+	return Declaration{
+		Name:     name,
+		Package:  fn.Pkg().Path(),
+		Position: newPosition(fset, fn.Pos(), token.NoPos),
+	}
+}
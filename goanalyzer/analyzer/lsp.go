@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LSP SymbolKind values, from the Language Server Protocol specification.
+const (
+	lspKindMethod    = 6
+	lspKindField     = 8
+	lspKindInterface = 11
+	lspKindStruct    = 23
+)
+
+type LSPPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type LSPRange struct {
+	Start LSPPosition `json:"start"`
+	End   LSPPosition `json:"end"`
+}
+
+// LSPSymbol mirrors the protocol's DocumentSymbol.
+type LSPSymbol struct {
+	Name           string      `json:"name"`
+	Detail         string      `json:"detail,omitempty"`
+	Kind           int         `json:"kind"`
+	Range          LSPRange    `json:"range"`
+	SelectionRange LSPRange    `json:"selectionRange"`
+	Children       []LSPSymbol `json:"children,omitempty"`
+}
+
+// LSPDocument holds the symbols of one file, in source order.
+type LSPDocument struct {
+	URI     string      `json:"uri"`
+	Package string      `json:"package"`
+	Symbols []LSPSymbol `json:"symbols"`
+}
+
+// LSPSymbolReport is the output of -format lsp-symbols.
+type LSPSymbolReport struct {
+	SchemaVersion    string        `json:"schemaVersion"`
+	GeneratorVersion string        `json:"generatorVersion"`
+	Documents        []LSPDocument `json:"documents"`
+}
+
+// buildLSPDocuments outlines the files of pkg the way an editor's document
+// outline shows them: interfaces with their methods, structs with their
+// fields, and methods as top-level symbols named like "(*T).M". Positions
+// are zero-based, with characters counted in UTF-16 code units as the
+// protocol requires by default.
+func buildLSPDocuments(pkg *packages.Package, sources *sourceFiles, style typeStyle, skipFile func(filename string) bool) []LSPDocument {
+	documents := make([]LSPDocument, 0, len(pkg.Syntax))
+	for _, file := range pkg.Syntax {
+		tokenFile := pkg.Fset.File(file.Pos())
+		if tokenFile == nil || skipFile(tokenFile.Name()) {
+			continue
+		}
+		src := sources.get(tokenFile.Name())
+		position := func(pos token.Pos) LSPPosition {
+			return lspPosition(tokenFile, src, pos)
+		}
+		rangeOf := func(node ast.Node) LSPRange {
+			return LSPRange{Start: position(node.Pos()), End: position(node.End())}
+		}
+		symbol := func(name, detail string, kind int, node, ident ast.Node) LSPSymbol {
+			return LSPSymbol{Name: name, Detail: detail, Kind: kind, Range: rangeOf(node), SelectionRange: rangeOf(ident)}
+		}
+
+		document := LSPDocument{
+			URI:     fileURI(tokenFile.Name()),
+			Package: pkg.PkgPath,
+			Symbols: make([]LSPSymbol, 0),
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					// An unparenthesized declaration spans the keyword too.
+					var node ast.Node = typeSpec
+					if !decl.Lparen.IsValid() {
+						node = decl
+					}
+
+					switch t := typeSpec.Type.(type) {
+					case *ast.InterfaceType:
+						s := symbol(typeSpec.Name.Name, "interface", lspKindInterface, node, typeSpec.Name)
+						for _, field := range t.Methods.List {
+							for _, name := range field.Names {
+								s.Children = append(s.Children, symbol(name.Name, strings.TrimPrefix(exprString(pkg, field.Type, style), "func"), lspKindMethod, field, name))
+							}
+						}
+						document.Symbols = append(document.Symbols, s)
+					case *ast.StructType:
+						s := symbol(typeSpec.Name.Name, "struct", lspKindStruct, node, typeSpec.Name)
+						for _, field := range t.Fields.List {
+							detail := exprString(pkg, field.Type, style)
+							if len(field.Names) == 0 {
+								s.Children = append(s.Children, symbol(detail, detail, lspKindField, field, field.Type))
+							}
+							for _, name := range field.Names {
+								s.Children = append(s.Children, symbol(name.Name, detail, lspKindField, field, name))
+							}
+						}
+						document.Symbols = append(document.Symbols, s)
+					}
+				}
+			case *ast.FuncDecl:
+				fn, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+				if !ok || decl.Recv == nil {
+					continue
+				}
+				recv := receiverName(fn)
+				if _, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+					recv = "*" + recv
+				}
+				detail := strings.TrimPrefix(style.typeString(fn.Type(), pkg.Types), "func")
+				document.Symbols = append(document.Symbols, symbol("("+recv+")."+fn.Name(), detail, lspKindMethod, decl, decl.Name))
+			}
+		}
+		documents = append(documents, document)
+	}
+	return documents
+}
+
+// exprString prints the type expression expr denotes, in pkg.
+func exprString(pkg *packages.Package, expr ast.Expr, style typeStyle) string {
+	if t := pkg.TypesInfo.TypeOf(expr); t != nil {
+		return style.typeString(t, pkg.Types)
+	}
+	return types.ExprString(expr)
+}
+
+// lspPosition converts pos to a zero-based line and UTF-16 character
+// offset. Without the source, the character offset is in bytes.
+func lspPosition(file *token.File, src []byte, pos token.Pos) LSPPosition {
+	line := file.Line(pos)
+	start, offset := file.Offset(file.LineStart(line)), file.Offset(pos)
+	character := offset - start
+	if src != nil && offset <= len(src) {
+		character = len(utf16.Encode([]rune(string(src[start:offset]))))
+	}
+	return LSPPosition{Line: line - 1, Character: character}
+}
+
+// fileURI returns the file:// URI of an absolute file name.
+func fileURI(filename string) string {
+	path := filepath.ToSlash(filename)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+func sortLSPDocuments(documents []LSPDocument) {
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].URI < documents[j].URI
+	})
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// writeMermaid emits result as a Mermaid class diagram: interfaces and
+// structs with their methods, realization arrows from structs to the
+// interfaces they implement, and composition arrows to the types they
+// embed. Classes list at most maxMethods methods unless it is 0.
+func writeMermaid(w io.Writer, result AnalysisResult, maxMethods int) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%% schemaVersion: %s, generatorVersion: %s\n", result.SchemaVersion, result.GeneratorVersion)
+	fmt.Fprintln(bw, "classDiagram")
+
+	seen := make(map[string]bool)
+	writeClass := func(name, pkg, stereotype string, methods []MethodInfo) string {
+		id := mermaidID(pkg + "." + name)
+		if seen[id] {
+			return id
+		}
+		seen[id] = true
+		fmt.Fprintf(bw, "    class %s[\"%s\"] {\n", id, mermaidText(name))
+		if stereotype != "" {
+			fmt.Fprintf(bw, "        <<%s>>\n", stereotype)
+		}
+		shown := methods
+		if maxMethods > 0 && len(shown) > maxMethods {
+			shown = shown[:maxMethods]
+		}
+		for _, method := range shown {
+			fmt.Fprintf(bw, "        %s\n", mermaidMethod(method))
+		}
+		if hidden := len(methods) - len(shown); hidden > 0 {
+			fmt.Fprintf(bw, "        ... %d more\n", hidden)
+		}
+		fmt.Fprintln(bw, "    }")
+		return id
+	}
+
+	for _, iface := range result.Interfaces {
+		writeClass(iface.Name, iface.Package, "interface", iface.Methods)
+	}
+	for _, iface := range result.ExternalInterfaces {
+		writeClass(iface.Name, iface.Package, "interface", iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		writeClass(strct.Name, strct.Package, "", strct.Methods)
+	}
+
+	names := newTypeNames(result)
+	for _, strct := range result.Structs {
+		id := mermaidID(strct.Package + "." + strct.Name)
+		for _, iface := range strct.ImplementedInterfaces {
+			target := writeClass(iface.Name, iface.Package, "interface", nil)
+			fmt.Fprintf(bw, "    %s <|.. %s\n", target, id)
+		}
+		// Embedded types may live outside the analyzed packages, so they
+		// get an empty class when missing.
+		for _, embedded := range strct.EmbeddedTypes {
+			name := names.resolve(embedded, strct.Package)
+			target := mermaidID(name)
+			if !seen[target] {
+				seen[target] = true
+				fmt.Fprintf(bw, "    class %s[\"%s\"]\n", target, mermaidText(name))
+			}
+			fmt.Fprintf(bw, "    %s *-- %s : embeds\n", id, target)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// mermaidMethod renders method as a class member, public when exported.
+// Mermaid takes the member's last parenthesis pair for the parameter list,
+// so parentheses within types are escaped.
+func mermaidMethod(method MethodInfo) string {
+	visibility := "-"
+	if token.IsExported(method.Name) {
+		visibility = "+"
+	}
+	params := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		params[i] = strings.TrimSpace(param.Name + " " + mermaidType(param.Type))
+	}
+	results := make([]string, len(method.ReturnTypes))
+	for i, result := range method.ReturnTypes {
+		results[i] = mermaidType(result)
+	}
+	return strings.TrimSpace(visibility + mermaidText(method.Name) + "(" + strings.Join(params, ", ") + ") " + strings.Join(results, ", "))
+}
+
+func mermaidType(t string) string {
+	return strings.NewReplacer("(", "#40;", ")", "#41;").Replace(mermaidText(t))
+}
+
+// mermaidID turns a qualified name into a class id, which Mermaid limits to
+// letters, digits and underscores. Other ASCII characters become
+// underscores and other letters their code point, so that "ñandú" and
+// "nandu" stay apart.
+func mermaidID(name string) string {
+	var id strings.Builder
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			id.WriteRune(r)
+		case r < unicode.MaxASCII:
+			id.WriteByte('_')
+		default:
+			fmt.Fprintf(&id, "u%04x", r)
+		}
+	}
+	return id.String()
+}
+
+// mermaidText escapes the characters that end a class body or label.
+func mermaidText(text string) string {
+	text = strings.ReplaceAll(text, "interface{}", "any")
+	return strings.NewReplacer(`"`, "#quot;", "{", "#123;", "}", "#125;").Replace(text)
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"os"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// ModuleInfo describes a module providing analyzed packages.
+type ModuleInfo struct {
+	Path      string `json:"path"`
+	Version   string `json:"version,omitempty"`
+	Main      bool   `json:"main,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
+	// ReplacedBy is set when the build uses another module in its place.
+	ReplacedBy *ModuleReplace `json:"replacedBy,omitempty"`
+	// Replaces are the replace directives of the module's own go.mod,
+	// which take effect when it is the main module.
+	Replaces []ModuleReplace `json:"replaces,omitempty"`
+}
+
+type ModuleReplace struct {
+	// Old is the replaced module path, with a version if only that version
+	// is replaced. It is empty for ReplacedBy.
+	Old     string `json:"old,omitempty"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// collectModules describes the given modules, ordered by path. Packages
+// loaded outside module mode have no module and contribute nothing.
+func collectModules(modules map[string]*packages.Module) []ModuleInfo {
+	infos := make([]ModuleInfo, 0, len(modules))
+	for _, module := range modules {
+		info := ModuleInfo{
+			Path:      module.Path,
+			Version:   module.Version,
+			Main:      module.Main,
+			GoVersion: module.GoVersion,
+		}
+		if module.Replace != nil {
+			info.ReplacedBy = &ModuleReplace{Path: module.Replace.Path, Version: module.Replace.Version}
+		}
+		info.Replaces = replaceDirectives(module.GoMod)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+	return infos
+}
+
+// replaceDirectives reads the replace directives of a go.mod file. An
+// unreadable file yields none: the module is still reported.
+func replaceDirectives(gomod string) []ModuleReplace {
+	if gomod == "" {
+		return nil
+	}
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return nil
+	}
+	file, err := modfile.Parse(gomod, data, nil)
+	if err != nil {
+		return nil
+	}
+
+	var replaces []ModuleReplace
+	for _, replace := range file.Replace {
+		old := replace.Old.Path
+		if replace.Old.Version != "" {
+			old += "@" + replace.Old.Version
+		}
+		replaces = append(replaces, ModuleReplace{
+			Old:     old,
+			Path:    replace.New.Path,
+			Version: replace.New.Version,
+		})
+	}
+	return replaces
+}
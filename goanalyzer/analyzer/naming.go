@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Naming checks go through these helpers rather than comparing bytes, so
+// that identifiers such as "HTTPServer", "IDRepository" or "Ärger" are
+// classified the way the Go spec does.
+
+// startsUpper reports whether s begins with an upper-case letter in any
+// script. Like go/token.IsExported, it looks at the first rune, not byte.
+func startsUpper(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
+}
+
+// splitIdentifier splits a mixed-caps identifier into words, keeping
+// acronyms together: "HTTPServer" gives ["HTTP", "Server"], "IDRepository"
+// gives ["ID", "Repository"] and "parseURLs" gives ["parse", "URLs"].
+func splitIdentifier(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := false
+		switch {
+		case cur == '_' || prev == '_':
+			boundary = true
+		case unicode.IsLower(prev) && unicode.IsUpper(cur):
+			// "parseURL": lower to upper starts a word.
+			boundary = true
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// "HTTPServer": the last capital of an acronym starts the next
+			// word, unless only a plural "s" follows, as in "URLs".
+			boundary = !(runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2])))
+		case unicode.IsDigit(prev) != unicode.IsDigit(cur) && unicode.IsLetter(cur) && unicode.IsUpper(cur):
+			boundary = true
+		}
+
+		if boundary {
+			if word := string(runes[start:i]); word != "_" && word != "" {
+				words = append(words, word)
+			}
+			start = i
+			if cur == '_' {
+				start = i + 1
+			}
+		}
+	}
+	if start < len(runes) {
+		if word := string(runes[start:]); word != "_" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"IDRepository", []string{"ID", "Repository"}},
+		{"parseURLs", []string{"parse", "URLs"}},
+		{"user2FA", []string{"user2", "FA"}},
+		{"x_y", []string{"x", "y"}},
+		{"_x", []string{"x"}},
+		{"ID", []string{"ID"}},
+		{"Ärger", []string{"Ärger"}},
+		{"Größe", []string{"Größe"}},
+		{"ñandú", []string{"ñandú"}},
+		{"Δelta", []string{"Δelta"}},
+		{"ÜberHTTPClient", []string{"Über", "HTTP", "Client"}},
+		{"ÉcoleID", []string{"École", "ID"}},
+		{"getΔValue", []string{"get", "Δ", "Value"}},
+		{"ΣφάλμαΧρόνου", []string{"Σφάλμα", "Χρόνου"}},
+	}
+	for _, tt := range tests {
+		if got := splitIdentifier(tt.name); !slices.Equal(got, tt.want) {
+			t.Errorf("splitIdentifier(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStartsUpper(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"", false},
+		{"HTTPServer", true},
+		{"parseURL", false},
+		{"_Exported", false},
+		{"Ärger", true},
+		{"ñandú", false},
+		{"Δelta", true},
+		{"δelta", false},
+		{"Ǆemal", true},
+		{"日本", false},
+	}
+	for _, tt := range tests {
+		if got := startsUpper(tt.name); got != tt.want {
+			t.Errorf("startsUpper(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NamingSuggestion proposes a more idiomatic name for an interface.
+type NamingSuggestion struct {
+	Interface Declaration `json:"interface"`
+	// Suggested is empty when there is no mechanical alternative.
+	Suggested string `json:"suggested,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// findNamingSuggestions checks interface names against the Go convention of
+// naming one-method interfaces after the method plus -er (Reader for Read),
+// and interfaces combining such methods after all of them (ReadCloser). The
+// check is a style nudge and only looks at names.
+func findNamingSuggestions(interfaces []InterfaceInfo) []NamingSuggestion {
+	suggestions := make([]NamingSuggestion, 0)
+	for _, iface := range interfaces {
+		switch {
+		case len(iface.Methods) == 1:
+			method := iface.Methods[0].Name
+			if isAgentNoun(iface.Name) || iface.Name == agentNoun(method) {
+				continue
+			}
+			suggestions = append(suggestions, NamingSuggestion{
+				Interface: interfaceDeclaration(iface),
+				Suggested: agentNoun(method),
+				Reason:    "one-method interfaces are named after their method with an -er suffix",
+			})
+		case len(iface.Methods) > 1:
+			// A name taken from a single method, such as Reader for an
+			// interface that can also Close, hides the other methods.
+			for _, method := range iface.Methods {
+				if iface.Name != agentNoun(method.Name) {
+					continue
+				}
+				suggestions = append(suggestions, NamingSuggestion{
+					Interface: interfaceDeclaration(iface),
+					Suggested: combinedName(iface.Methods),
+					Reason:    fmt.Sprintf("the name only describes %s of its %d methods", method.Name, len(iface.Methods)),
+				})
+				break
+			}
+		}
+	}
+	return suggestions
+}
+
+// isAgentNoun reports whether the last word of name ends in -er or -or.
+func isAgentNoun(name string) bool {
+	words := splitIdentifier(name)
+	if len(words) == 0 {
+		return false
+	}
+	last := strings.ToLower(words[len(words)-1])
+	return strings.HasSuffix(last, "er") || strings.HasSuffix(last, "or")
+}
+
+// agentNoun turns a method name into the conventional interface name:
+// Read gives Reader, Close gives Closer and Get gives Getter.
+func agentNoun(method string) string {
+	words := splitIdentifier(method)
+	if len(words) == 0 {
+		return method
+	}
+	last := words[len(words)-1]
+	switch {
+	case strings.HasSuffix(last, "e"):
+		return method + "r"
+	case doublesFinalConsonant(last):
+		return method + last[len(last)-1:] + "er"
+	default:
+		return method + "er"
+	}
+}
+
+// doublesFinalConsonant reports whether word is a one-syllable word ending
+// in consonant-vowel-consonant, such as Get or Scan, whose final consonant
+// doubles before -er. It is a spelling heuristic for ASCII words.
+func doublesFinalConsonant(word string) bool {
+	vowel := func(b byte) bool { return strings.IndexByte("aeiouAEIOU", b) >= 0 }
+	n := len(word)
+	if n < 3 || vowel(word[n-3]) || !vowel(word[n-2]) || vowel(word[n-1]) || strings.IndexByte("wxyWXY", word[n-1]) >= 0 {
+		return false
+	}
+	for i := 0; i < n-2; i++ {
+		if vowel(word[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// combinedName joins the method names in declaration order, ending in the
+// -er form of the last one, as in ReadWriteCloser. Interfaces with more than
+// three methods are better named after what they represent.
+func combinedName(methods []MethodInfo) string {
+	if len(methods) > 3 {
+		return ""
+	}
+	ordered := append([]MethodInfo(nil), methods...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return positionLess(ordered[i].Position, ordered[j].Position)
+	})
+	name := ""
+	for i, method := range ordered {
+		if i == len(ordered)-1 {
+			name += agentNoun(method.Name)
+		} else {
+			name += method.Name
+		}
+	}
+	return name
+}
@@ -0,0 +1,60 @@
+package analyzer
+
+import "sort"
+
+// InterfaceOrder compares the declaration order of a struct's methods with
+// the interface it implements.
+type InterfaceOrder struct {
+	Interface Declaration `json:"interface"`
+	// Similarity is the fraction of pairs of the interface's methods that
+	// the struct declares in the same relative order: 1 when the struct
+	// mirrors the interface, 0 when it reverses it.
+	Similarity float64 `json:"similarity"`
+}
+
+// methodOrderSimilarity compares the order of the methods strct declares
+// itself with their order in iface. It is best-effort: order across files
+// follows file names, and there is nothing to compare, so ok is false,
+// unless the struct declares at least two of the interface's methods.
+func methodOrderSimilarity(strct *StructInfo, iface InterfaceInfo) (similarity float64, ok bool) {
+	declared := make(map[string]Position)
+	for _, method := range strct.Methods {
+		if !method.Promoted {
+			declared[method.Name] = method.Position
+		}
+	}
+
+	ifaceMethods := make([]MethodInfo, 0, len(iface.Methods))
+	for _, method := range iface.Methods {
+		if _, ok := declared[method.Name]; ok {
+			ifaceMethods = append(ifaceMethods, method)
+		}
+	}
+	if len(ifaceMethods) < 2 {
+		return 0, false
+	}
+	sort.SliceStable(ifaceMethods, func(i, j int) bool {
+		return positionLess(ifaceMethods[i].Position, ifaceMethods[j].Position)
+	})
+
+	pairs, inOrder := 0, 0
+	for i := range ifaceMethods {
+		for j := i + 1; j < len(ifaceMethods); j++ {
+			pairs++
+			if positionLess(declared[ifaceMethods[i].Name], declared[ifaceMethods[j].Name]) {
+				inOrder++
+			}
+		}
+	}
+	return float64(inOrder) / float64(pairs), true
+}
+
+func positionLess(a, b Position) bool {
+	if a.Path != b.Path {
+		return a.Path < b.Path
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
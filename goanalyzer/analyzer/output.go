@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Formats lists the output formats Write accepts.
+var Formats = []string{"json", "api", "toml", "cypher", "text", "index", "fingerprint", "lsp-symbols", "deps-dot", "dot", "mermaid", "plantuml", "sarif"}
+
+// IsValidFormat reports whether format is one of Formats.
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func writeResult(w io.Writer, result AnalysisResult, format string, opts Options) error {
+	switch format {
+	case "json":
+		return writeJSON(w, result)
+	case "toml":
+		return writeTOML(w, result)
+	case "cypher":
+		return writeCypher(w, result)
+	case "text":
+		return writeText(w, result)
+	case "index":
+		return writeJSON(w, buildMethodIndex(result))
+	case "fingerprint":
+		return writeJSON(w, buildFingerprints(result))
+	case "lsp-symbols":
+		return writeJSON(w, LSPSymbolReport{
+			SchemaVersion:    result.SchemaVersion,
+			GeneratorVersion: result.GeneratorVersion,
+			Documents:        result.LSPSymbols,
+		})
+	case "mermaid":
+		return writeMermaid(w, result, opts.MaxMethods)
+	case "plantuml":
+		return writePlantUML(w, result, opts.MaxMethods, opts.GroupPackages)
+	case "sarif":
+		return writeSARIF(w, result)
+	case "dot":
+		return writeDot(w, result)
+	case "deps-dot":
+		return writeDepsDot(w, result)
+	case "api":
+		return writeJSON(w, APIReport{
+			SchemaVersion:    result.SchemaVersion,
+			GeneratorVersion: result.GeneratorVersion,
+			Packages:         result.API,
+		})
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
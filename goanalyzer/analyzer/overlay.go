@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"os"
+	"sync"
+)
+
+// sourceFiles reads file contents once each, from the overlay where it has
+// them and from disk otherwise. Files that cannot be read are nil. It is
+// safe for concurrent use.
+type sourceFiles struct {
+	overlay map[string][]byte
+
+	mu   sync.Mutex
+	read map[string][]byte
+}
+
+func newSourceFiles(overlay map[string][]byte) *sourceFiles {
+	return &sourceFiles{overlay: overlay, read: make(map[string][]byte)}
+}
+
+func (s *sourceFiles) get(filename string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.read[filename]
+	if !ok {
+		src = s.overlay[filename]
+		if src == nil {
+			src, _ = os.ReadFile(filename)
+		}
+		s.read[filename] = src
+	}
+	return src
+}
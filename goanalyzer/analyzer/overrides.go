@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findOverridden returns the method that method, declared directly on a
+// struct type, shadows in one of the struct's embedded fields, or nil.
+func findOverridden(strct *types.Struct, method *types.Func) *types.Func {
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		if !field.Anonymous() {
+			continue
+		}
+
+		obj, _, _ := types.LookupFieldOrMethod(field.Type(), true, method.Pkg(), method.Name())
+		if shadowed, ok := obj.(*types.Func); ok {
+			return shadowed
+		}
+	}
+	return nil
+}
+
+func overriddenDeclaration(fn *types.Func, pkg *packages.Package) *Declaration {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		recvType := recv.Type()
+		if ptr, ok := recvType.(*types.Pointer); ok {
+			recvType = ptr.Elem()
+		}
+		if named, ok := recvType.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+
+	decl := &Declaration{Name: name}
+	if fn.Pkg() != nil {
+		decl.Package = fn.Pkg().Path()
+	}
+	decl.Position = newPosition(pkg.Fset, fn.Pos(), token.NoPos)
+	return decl
+}
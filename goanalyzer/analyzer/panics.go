@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+type panicSites struct {
+	panics   []Position
+	repanics []Position
+}
+
+// collectPanicSites locates the calls to the built-in panic in every
+// function and method body, keyed by the position of the declaring
+// function's name. A call passing on a value obtained from recover() in the
+// same body, as in
+//
+//	if r := recover(); r != nil {
+//		panic(r)
+//	}
+//
+// is a re-panic. Values that reach panic any other way, say wrapped in an
+// error, count as new panics.
+func collectPanicSites(pkgs []*packages.Package) map[Position]panicSites {
+	sites := make(map[Position]panicSites)
+	forEachFuncBody(pkgs, func(pkg *packages.Package, key Position, decl *ast.FuncDecl) {
+		recovered := make(map[types.Object]bool)
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != len(assign.Rhs) {
+				return true
+			}
+			for i, rhs := range assign.Rhs {
+				ident, ok := assign.Lhs[i].(*ast.Ident)
+				if ok && isBuiltinCall(pkg, rhs, "recover") {
+					if obj := pkg.TypesInfo.ObjectOf(ident); obj != nil {
+						recovered[obj] = true
+					}
+				}
+			}
+			return true
+		})
+
+		var found panicSites
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isBuiltinCall(pkg, call, "panic") {
+				return true
+			}
+			site := newPosition(pkg.Fset, call.Pos(), call.End())
+			if len(call.Args) == 0 {
+				return true
+			}
+			if arg, ok := call.Args[0].(*ast.Ident); ok && recovered[pkg.TypesInfo.ObjectOf(arg)] {
+				found.repanics = append(found.repanics, site)
+			} else {
+				found.panics = append(found.panics, site)
+			}
+			return true
+		})
+		sites[key] = found
+	})
+	return sites
+}
+
+// isBuiltinCall reports whether expr calls the named built-in function.
+func isBuiltinCall(pkg *packages.Package, expr ast.Expr, name string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := astutil.Unparen(call.Fun).(*ast.Ident)
+	if !ok || ident.Name != name {
+		return false
+	}
+	_, ok = pkg.TypesInfo.Uses[ident].(*types.Builtin)
+	return ok
+}
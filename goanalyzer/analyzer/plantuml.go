@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+)
+
+// plantUMLClass is a class of the diagram, declared before the edges.
+type plantUMLClass struct {
+	id, name, pkg, kind string
+	methods             []MethodInfo
+}
+
+// writePlantUML emits result as a PlantUML class diagram: interfaces and
+// structs with their methods, realization arrows from structs to the
+// interfaces they implement, and composition arrows to the types they
+// embed. Classes list at most maxMethods methods unless it is 0, and are
+// grouped into one PlantUML package per Go package with groupPackages.
+func writePlantUML(w io.Writer, result AnalysisResult, maxMethods int, groupPackages bool) error {
+	var classes []plantUMLClass
+	seen := make(map[string]bool)
+	addClass := func(name, pkg, kind string, methods []MethodInfo) string {
+		id := mermaidID(pkg + "." + name)
+		if !seen[id] {
+			seen[id] = true
+			classes = append(classes, plantUMLClass{id: id, name: name, pkg: pkg, kind: kind, methods: methods})
+		}
+		return id
+	}
+
+	for _, iface := range result.Interfaces {
+		addClass(iface.Name, iface.Package, "interface", iface.Methods)
+	}
+	for _, iface := range result.ExternalInterfaces {
+		addClass(iface.Name, iface.Package, "interface", iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		addClass(strct.Name, strct.Package, "class", strct.Methods)
+	}
+
+	var edges []string
+	names := newTypeNames(result)
+	for _, strct := range result.Structs {
+		id := mermaidID(strct.Package + "." + strct.Name)
+		for _, iface := range strct.ImplementedInterfaces {
+			target := addClass(iface.Name, iface.Package, "interface", nil)
+			edge := fmt.Sprintf("%s <|.. %s", target, id)
+			if len(iface.TypeArguments) > 0 {
+				edge += fmt.Sprintf(" : %s[%s]", iface.Name, strings.Join(iface.TypeArguments, ", "))
+			}
+			edges = append(edges, edge)
+		}
+		// Embedded types may live outside the analyzed packages, so they
+		// get an empty class, outside any package, when missing.
+		for _, embedded := range strct.EmbeddedTypes {
+			name := names.resolve(embedded, strct.Package)
+			target := mermaidID(name)
+			if !seen[target] {
+				seen[target] = true
+				classes = append(classes, plantUMLClass{id: target, name: name, kind: "class"})
+			}
+			edges = append(edges, fmt.Sprintf("%s *-- %s : embeds", id, target))
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "@startuml")
+	fmt.Fprintf(bw, "' schemaVersion: %s, generatorVersion: %s\n", result.SchemaVersion, result.GeneratorVersion)
+	// Qualified names are labels, not namespaces.
+	fmt.Fprintln(bw, "set separator none")
+	fmt.Fprintln(bw, "hide empty members")
+
+	writeClass := func(class plantUMLClass, indent string) {
+		fmt.Fprintf(bw, "%s%s %s as %s", indent, class.kind, plantUMLString(class.name), class.id)
+		if len(class.methods) == 0 {
+			fmt.Fprintln(bw)
+			return
+		}
+		fmt.Fprintln(bw, " {")
+		shown := class.methods
+		if maxMethods > 0 && len(shown) > maxMethods {
+			shown = shown[:maxMethods]
+		}
+		for _, method := range shown {
+			fmt.Fprintf(bw, "%s  %s\n", indent, plantUMLMethod(method))
+		}
+		if hidden := len(class.methods) - len(shown); hidden > 0 {
+			fmt.Fprintf(bw, "%s  .. %d more ..\n", indent, hidden)
+		}
+		fmt.Fprintf(bw, "%s}\n", indent)
+	}
+
+	if groupPackages {
+		byPackage := make(map[string][]plantUMLClass)
+		for _, class := range classes {
+			byPackage[class.pkg] = append(byPackage[class.pkg], class)
+		}
+		pkgs := make([]string, 0, len(byPackage))
+		for pkg := range byPackage {
+			pkgs = append(pkgs, pkg)
+		}
+		sort.Strings(pkgs)
+		for _, pkg := range pkgs {
+			if pkg == "" {
+				continue
+			}
+			fmt.Fprintf(bw, "package %s {\n", plantUMLString(pkg))
+			for _, class := range byPackage[pkg] {
+				writeClass(class, "  ")
+			}
+			fmt.Fprintln(bw, "}")
+		}
+		for _, class := range byPackage[""] {
+			writeClass(class, "")
+		}
+	} else {
+		for _, class := range classes {
+			writeClass(class, "")
+		}
+	}
+
+	for _, edge := range edges {
+		fmt.Fprintln(bw, edge)
+	}
+	fmt.Fprintln(bw, "@enduml")
+	return bw.Flush()
+}
+
+// plantUMLMethod renders method as a class member, public when exported.
+// PlantUML ends a class body at a line holding "}", and takes a member
+// starting with "{" for a modifier, so braces are kept off line ends.
+func plantUMLMethod(method MethodInfo) string {
+	visibility := "-"
+	if token.IsExported(method.Name) {
+		visibility = "+"
+	}
+	params := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		params[i] = strings.TrimSpace(param.Name + " " + plantUMLType(param.Type))
+	}
+	results := make([]string, len(method.ReturnTypes))
+	for i, result := range method.ReturnTypes {
+		results[i] = plantUMLType(result)
+	}
+	return strings.TrimSpace(visibility + method.Name + "(" + strings.Join(params, ", ") + ") " + strings.Join(results, ", "))
+}
+
+func plantUMLType(t string) string {
+	return strings.ReplaceAll(t, "interface{}", "any")
+}
+
+// plantUMLString quotes a class or package name, which PlantUML reads
+// verbatim between double quotes.
+func plantUMLString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `'`) + `"`
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// receiverType returns the named type method is declared on, or nil for
+// methods of unnamed interface types.
+func receiverType(method *types.Func) *types.Named {
+	recv := method.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+// receiverName returns the name of the type method is declared on.
+func receiverName(method *types.Func) string {
+	if named := receiverType(method); named != nil {
+		return named.Obj().Name()
+	}
+	return types.TypeString(method.Type().(*types.Signature).Recv().Type(), nil)
+}
+
+// receiverDeclaration describes the type method is declared on. For a
+// promoted method that is the embedded type, not the one it is promoted to.
+func receiverDeclaration(method *types.Func, fset *token.FileSet) *Declaration {
+	named := receiverType(method)
+	if named == nil {
+		return nil
+	}
+	obj := named.Origin().Obj()
+	decl := &Declaration{Name: obj.Name()}
+	if obj.Pkg() != nil {
+		decl.Package = obj.Pkg().Path()
+	}
+	decl.Position = newPosition(fset, obj.Pos(), token.NoPos)
+	return decl
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// RedundantMethod is a method an interface gets more than once: declared
+// explicitly and through an embedded interface, or through several embedded
+// interfaces. The compiler accepts this when the signatures are identical,
+// which is how generated or merged interfaces keep redundant parts.
+type RedundantMethod struct {
+	Interface Declaration `json:"interface"`
+	Method    string      `json:"method"`
+	// Sources name the explicit method or embedded interface each copy
+	// comes from, positioned at the method declaration reached.
+	Sources []Declaration `json:"sources"`
+}
+
+func findRedundantMethods(interfaces []InterfaceInfo, ifaceTypes []*types.Interface, fset *token.FileSet) []RedundantMethod {
+	redundant := make([]RedundantMethod, 0)
+	position := func(method *types.Func) Position {
+		return newPosition(fset, method.Pos(), token.NoPos)
+	}
+
+	for i, iface := range ifaceTypes {
+		var names []string
+		sources := make(map[string][]Declaration)
+		add := func(name string, source Declaration) {
+			if _, ok := sources[name]; !ok {
+				names = append(names, name)
+			}
+			sources[name] = append(sources[name], source)
+		}
+
+		for j := 0; j < iface.NumExplicitMethods(); j++ {
+			method := iface.ExplicitMethod(j)
+			add(method.Name(), Declaration{
+				Name:     interfaces[i].Name + "." + method.Name(),
+				Package:  interfaces[i].Package,
+				Position: position(method),
+			})
+		}
+		for j := 0; j < iface.NumEmbeddeds(); j++ {
+			embedded := iface.EmbeddedType(j)
+			embeddedIface, ok := embedded.Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			name, pkg := types.TypeString(embedded, nil), ""
+			if named, ok := embedded.(*types.Named); ok {
+				name = named.Obj().Name()
+				if named.Obj().Pkg() != nil {
+					pkg = named.Obj().Pkg().Path()
+				}
+			}
+			for k := 0; k < embeddedIface.NumMethods(); k++ {
+				method := embeddedIface.Method(k)
+				add(method.Name(), Declaration{
+					Name:     name + "." + method.Name(),
+					Package:  pkg,
+					Position: position(method),
+				})
+			}
+		}
+
+		for _, name := range names {
+			if len(sources[name]) > 1 {
+				redundant = append(redundant, RedundantMethod{
+					Interface: interfaceDeclaration(interfaces[i]),
+					Method:    name,
+					Sources:   sources[name],
+				})
+			}
+		}
+	}
+	return redundant
+}
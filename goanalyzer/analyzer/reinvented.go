@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultStdlibTypes are common standard library types whose method sets
+// already match many hand-written interfaces.
+var DefaultStdlibTypes = []string{
+	"*bytes.Buffer",
+	"*bytes.Reader",
+	"*strings.Builder",
+	"*strings.Reader",
+	"*bufio.Reader",
+	"*bufio.Writer",
+	"*os.File",
+	"*net.TCPConn",
+}
+
+type ReinventedInterface struct {
+	Interface   Declaration `json:"interface"`
+	SatisfiedBy []string    `json:"satisfiedBy"`
+}
+
+type stdlibType struct {
+	name string
+	typ  types.Type
+}
+
+// loadStdlibTypes resolves names such as "*bytes.Buffer" to their types.
+func loadStdlibTypes(ctx context.Context, names []string) ([]stdlibType, error) {
+	type ref struct {
+		name, pkgPath, typeName string
+		pointer                 bool
+	}
+
+	refs := make([]ref, 0, len(names))
+	patterns := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		qualified := strings.TrimPrefix(name, "*")
+		dot := strings.LastIndex(qualified, ".")
+		if dot <= 0 {
+			return nil, fmt.Errorf("invalid type %q: want [*]package.Type", name)
+		}
+
+		r := ref{name: name, pkgPath: qualified[:dot], typeName: qualified[dot+1:], pointer: qualified != name}
+		refs = append(refs, r)
+		if !seen[r.pkgPath] {
+			seen[r.pkgPath] = true
+			patterns = append(patterns, r.pkgPath)
+		}
+	}
+
+	cfg := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedTypes,
+		Context: ctx,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]*types.Package)
+	for _, pkg := range pkgs {
+		if pkg.Types != nil {
+			byPath[pkg.PkgPath] = pkg.Types
+		}
+	}
+
+	resolved := make([]stdlibType, 0, len(refs))
+	for _, r := range refs {
+		pkg, ok := byPath[r.pkgPath]
+		if !ok {
+			return nil, fmt.Errorf("package %s not found for type %s", r.pkgPath, r.name)
+		}
+		obj, ok := pkg.Scope().Lookup(r.typeName).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", r.name)
+		}
+
+		var typ types.Type = obj.Type()
+		if r.pointer {
+			typ = types.NewPointer(typ)
+		}
+		resolved = append(resolved, stdlibType{name: r.name, typ: typ})
+	}
+	return resolved, nil
+}
+
+// findReinventedInterfaces returns the interfaces that standard library
+// types already satisfy, hinting that an existing interface such as
+// io.Reader could be reused instead.
+func findReinventedInterfaces(interfaces []InterfaceInfo, ifaceTypes []*types.Interface, candidates []stdlibType) []ReinventedInterface {
+	reinvented := make([]ReinventedInterface, 0)
+	for i, iface := range interfaces {
+		var satisfiedBy []string
+		for _, candidate := range candidates {
+			if types.Implements(candidate.typ, ifaceTypes[i]) {
+				satisfiedBy = append(satisfiedBy, candidate.name)
+			}
+		}
+
+		if len(satisfiedBy) > 0 {
+			reinvented = append(reinvented, ReinventedInterface{
+				Interface:   interfaceDeclaration(iface),
+				SatisfiedBy: satisfiedBy,
+			})
+		}
+	}
+	return reinvented
+}
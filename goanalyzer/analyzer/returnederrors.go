@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// collectReturnedErrors finds, for every function and method whose last
+// result is an error, the errors its return statements construct, keyed by
+// the position of the declared name. Each is reported as:
+//
+//   - the concrete type, for values of a type implementing error, as in
+//     return &NotFoundError{ID: id};
+//   - the type errors.New and fmt.Errorf return, "*errors.errorString" or,
+//     when wrapping with %w, "*fmt.wrapError" or "*fmt.wrapErrors";
+//   - the qualified name, for package-level error variables such as io.EOF.
+//
+// This is a best-effort look at the return statements alone. Errors passed
+// on from calls or held in local variables have no type known statically
+// and are left out, as are the returns of function literals.
+func collectReturnedErrors(pkgs []*packages.Package, style typeStyle) map[Position][]string {
+	returned := make(map[Position][]string)
+	forEachFuncBody(pkgs, func(pkg *packages.Package, key Position, decl *ast.FuncDecl) {
+		fn, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+		if !ok {
+			return
+		}
+		results := fn.Type().(*types.Signature).Results()
+		if results.Len() == 0 || !types.Identical(results.At(results.Len()-1).Type(), errorType) {
+			return
+		}
+
+		found := make(map[string]bool)
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				if len(n.Results) == results.Len() {
+					if name := constructedError(pkg, n.Results[len(n.Results)-1], style); name != "" {
+						found[name] = true
+					}
+				}
+			}
+			return true
+		})
+
+		names := make([]string, 0, len(found))
+		for name := range found {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		returned[key] = names
+	})
+	return returned
+}
+
+// constructedError names the error expr evaluates to, or returns "" when
+// that is not known statically.
+func constructedError(pkg *packages.Package, expr ast.Expr, style typeStyle) string {
+	expr = astutil.Unparen(expr)
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.IsNil() {
+		return ""
+	}
+	if !types.IsInterface(tv.Type) {
+		return style.typeString(tv.Type, pkg.Types)
+	}
+
+	switch expr := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		var ident *ast.Ident
+		if sel, ok := expr.(*ast.SelectorExpr); ok {
+			ident = sel.Sel
+		} else {
+			ident = expr.(*ast.Ident)
+		}
+		v, ok := pkg.TypesInfo.Uses[ident].(*types.Var)
+		if ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+			return v.Pkg().Path() + "." + v.Name()
+		}
+	case *ast.CallExpr:
+		fn := typeutil.StaticCallee(pkg.TypesInfo, expr)
+		if fn == nil || fn.Pkg() == nil {
+			return ""
+		}
+		switch fn.Pkg().Path() + "." + fn.Name() {
+		case "errors.New":
+			return "*errors.errorString"
+		case "fmt.Errorf":
+			if len(expr.Args) == 0 {
+				return ""
+			}
+			tv, ok := pkg.TypesInfo.Types[expr.Args[0]]
+			if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+				return ""
+			}
+			switch strings.Count(constant.StringVal(tv.Value), "%w") {
+			case 0:
+				return "*errors.errorString"
+			case 1:
+				return "*fmt.wrapError"
+			default:
+				return "*fmt.wrapErrors"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,319 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sarifCheck is a section listing problems, reported as a SARIF rule.
+type sarifCheck struct {
+	section     string
+	description string
+	level       string
+	findings    func(r AnalysisResult) []sarifFinding
+}
+
+type sarifFinding struct {
+	message  string
+	position Position
+}
+
+// sarifChecks are the sections -fail-on accepts, in the order their results
+// are written. Package errors are left out: they carry no position, and
+// code scanning requires one.
+var sarifChecks = []sarifCheck{
+	{
+		section:     "cycles",
+		description: "Packages import each other, or struct types embed each other",
+		level:       "error",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.Cycles))
+			for _, cycle := range r.Cycles {
+				what := "Import cycle"
+				if cycle.Kind == "type" {
+					what = "Embedding cycle"
+				}
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("%s: %s", what, strings.Join(cycle.Path, " -> ")),
+					position: cycle.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "leakyAPIs",
+		description: "An exported declaration exposes an unexported type",
+		level:       "warning",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.LeakyAPIs))
+			for _, leak := range r.LeakyAPIs {
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("%s exposes unexported type %s", leak.Declaration.Name, leak.Type),
+					position: leak.Declaration.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "typeAssertions",
+		description: "A single-result type assertion panics when it fails",
+		level:       "warning",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0)
+			for _, assertion := range r.TypeAssertions {
+				if assertion.Safe {
+					continue
+				}
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("Type assertion of %s to %s panics if it fails; use the two-result form", assertion.From, assertion.To),
+					position: assertion.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "valueCopyWarnings",
+		description: "A struct is copied into an interface",
+		level:       "warning",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.ValueCopyWarnings))
+			for _, warning := range r.ValueCopyWarnings {
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("Passing %s as %s %s", warning.Type, warning.Interface, warning.Reason),
+					position: warning.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "ignoredContexts",
+		description: "A method takes a context.Context it never uses",
+		level:       "warning",
+		findings: func(r AnalysisResult) []sarifFinding {
+			return declarationFindings(r.IgnoredContexts, "%s never uses its context.Context parameter")
+		},
+	},
+	{
+		section:     "uncalledInterfaces",
+		description: "No method of an interface is called through an interface value",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			return declarationFindings(r.UncalledInterfaces, "No method of interface %s is called through an interface value")
+		},
+	},
+	{
+		section:     "redundantMethods",
+		description: "An interface gets the same method from several sources",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.RedundantMethods))
+			for _, redundant := range r.RedundantMethods {
+				sources := make([]string, len(redundant.Sources))
+				for i, source := range redundant.Sources {
+					sources[i] = source.Name
+				}
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("Interface %s gets method %s from %s", redundant.Interface.Name, redundant.Method, strings.Join(sources, " and ")),
+					position: redundant.Interface.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "reinventedInterfaces",
+		description: "Standard library types already satisfy an interface",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.ReinventedInterfaces))
+			for _, reinvented := range r.ReinventedInterfaces {
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("Interface %s is satisfied by %s", reinvented.Interface.Name, strings.Join(reinvented.SatisfiedBy, ", ")),
+					position: reinvented.Interface.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "tooManyParams",
+		description: "A method has more parameters than -max-params allows",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.TooManyParams))
+			for _, violation := range r.TooManyParams {
+				findings = append(findings, sarifFinding{
+					message:  fmt.Sprintf("%s has %d parameters", violation.Declaration.Name, violation.ParamCount),
+					position: violation.Declaration.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "namingSuggestions",
+		description: "An interface name departs from the -er convention",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			findings := make([]sarifFinding, 0, len(r.NamingSuggestions))
+			for _, suggestion := range r.NamingSuggestions {
+				message := fmt.Sprintf("Interface %s: %s", suggestion.Interface.Name, suggestion.Reason)
+				if suggestion.Suggested != "" {
+					message += fmt.Sprintf("; consider %s", suggestion.Suggested)
+				}
+				findings = append(findings, sarifFinding{
+					message:  message,
+					position: suggestion.Interface.Position,
+				})
+			}
+			return findings
+		},
+	},
+	{
+		section:     "deprecated",
+		description: "A declaration is marked deprecated",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			return declarationFindings(r.Deprecated, "%s is deprecated")
+		},
+	},
+	{
+		section:     "undocumented",
+		description: "An exported declaration has no doc comment",
+		level:       "note",
+		findings: func(r AnalysisResult) []sarifFinding {
+			return declarationFindings(r.Undocumented, "%s has no doc comment")
+		},
+	},
+}
+
+// declarationFindings reports each of decls, naming it in format.
+func declarationFindings(decls []Declaration, format string) []sarifFinding {
+	findings := make([]sarifFinding, 0, len(decls))
+	for _, decl := range decls {
+		findings = append(findings, sarifFinding{
+			message:  fmt.Sprintf(format, decl.Name),
+			position: decl.Position,
+		})
+	}
+	return findings
+}
+
+// The SARIF 2.1.0 subset written: one run, with a rule per check and a
+// result per finding.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+// Paths are relative to the module root, which SARIF consumers such as
+// GitHub code scanning resolve as %SRCROOT%.
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// writeSARIF reports the findings of the checks in result as a SARIF log,
+// for upload to code scanning services. Sections left out of result have
+// no findings.
+func writeSARIF(w io.Writer, result AnalysisResult) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:    "goanalyzer",
+			Version: result.GeneratorVersion,
+			Rules:   make([]sarifRule, len(sarifChecks)),
+		}},
+		Results: make([]sarifResult, 0),
+	}
+	for i, check := range sarifChecks {
+		run.Tool.Driver.Rules[i] = sarifRule{
+			ID:                   check.section,
+			ShortDescription:     sarifMessage{Text: check.description},
+			DefaultConfiguration: sarifConfiguration{Level: check.level},
+		}
+		for _, finding := range check.findings(result) {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: finding.position.Path, URIBaseID: "%SRCROOT%"},
+			}}
+			if finding.position.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   finding.position.Line,
+					StartColumn: finding.position.Column,
+					EndLine:     finding.position.EndLine,
+					EndColumn:   finding.position.EndColumn,
+				}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    check.section,
+				RuleIndex: i,
+				Level:     check.level,
+				Message:   sarifMessage{Text: finding.message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+	return writeJSON(w, sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
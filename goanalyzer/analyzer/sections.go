@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// section describes a result section -sections can select. Errors are
+// always reported.
+type section struct {
+	// drop removes the section from a result.
+	drop func(*AnalysisResult)
+	// count returns the number of findings in the section. It is set for
+	// the sections -fail-on accepts: those listing problems.
+	count func(AnalysisResult) int
+}
+
+var sections = map[string]section{
+	"interfaces": {drop: func(r *AnalysisResult) { r.Interfaces = nil }},
+	"structs":    {drop: func(r *AnalysisResult) { r.Structs = nil }},
+	"functions":  {drop: func(r *AnalysisResult) { r.Functions = nil }},
+	"leakyAPIs": {
+		drop:  func(r *AnalysisResult) { r.LeakyAPIs = nil },
+		count: func(r AnalysisResult) int { return len(r.LeakyAPIs) },
+	},
+	"uncalledInterfaces": {
+		drop:  func(r *AnalysisResult) { r.UncalledInterfaces = nil },
+		count: func(r AnalysisResult) int { return len(r.UncalledInterfaces) },
+	},
+	"interfaceHierarchy": {drop: func(r *AnalysisResult) { r.InterfaceHierarchy = nil }},
+	"reinventedInterfaces": {
+		drop:  func(r *AnalysisResult) { r.ReinventedInterfaces = nil },
+		count: func(r AnalysisResult) int { return len(r.ReinventedInterfaces) },
+	},
+	"externalInterfaces":         {drop: func(r *AnalysisResult) { r.ExternalInterfaces = nil }},
+	"errorTypes":                 {drop: func(r *AnalysisResult) { r.ErrorTypes = nil }},
+	"fluentTypes":                {drop: func(r *AnalysisResult) { r.FluentTypes = nil }},
+	"decorators":                 {drop: func(r *AnalysisResult) { r.Decorators = nil }},
+	"extractInterfaceCandidates": {drop: func(r *AnalysisResult) { r.ExtractInterfaceCandidates = nil }},
+	"ignoredContexts": {
+		drop:  func(r *AnalysisResult) { r.IgnoredContexts = nil },
+		count: func(r AnalysisResult) int { return len(r.IgnoredContexts) },
+	},
+	"conversions": {drop: func(r *AnalysisResult) { r.Conversions = nil }},
+	// Only the single-result assertions, which can panic, count as
+	// problems.
+	"typeAssertions": {
+		drop:  func(r *AnalysisResult) { r.TypeAssertions = nil },
+		count: func(r AnalysisResult) int { return unsafeAssertions(r.TypeAssertions) },
+	},
+	"emptyStructUses": {drop: func(r *AnalysisResult) { r.EmptyStructUses = nil }},
+	"valueCopyWarnings": {
+		drop:  func(r *AnalysisResult) { r.ValueCopyWarnings = nil },
+		count: func(r AnalysisResult) int { return len(r.ValueCopyWarnings) },
+	},
+	"enums":          {drop: func(r *AnalysisResult) { r.Enums = nil }},
+	"instantiations": {drop: func(r *AnalysisResult) { r.Instantiations = nil }},
+	"modules":        {drop: func(r *AnalysisResult) { r.Modules = nil }},
+	"redundantMethods": {
+		drop:  func(r *AnalysisResult) { r.RedundantMethods = nil },
+		count: func(r AnalysisResult) int { return len(r.RedundantMethods) },
+	},
+	"deprecated": {
+		drop:  func(r *AnalysisResult) { r.Deprecated = nil },
+		count: func(r AnalysisResult) int { return len(r.Deprecated) },
+	},
+	"undocumented": {
+		drop:  func(r *AnalysisResult) { r.Undocumented = nil },
+		count: func(r AnalysisResult) int { return len(r.Undocumented) },
+	},
+	"examples": {drop: func(r *AnalysisResult) { r.Examples = nil }},
+	"tooManyParams": {
+		drop:  func(r *AnalysisResult) { r.TooManyParams = nil },
+		count: func(r AnalysisResult) int { return len(r.TooManyParams) },
+	},
+	"namingSuggestions": {
+		drop:  func(r *AnalysisResult) { r.NamingSuggestions = nil },
+		count: func(r AnalysisResult) int { return len(r.NamingSuggestions) },
+	},
+	"localTypes":     {drop: func(r *AnalysisResult) { r.LocalTypes = nil }},
+	"callGraph":      {drop: func(r *AnalysisResult) { r.CallGraph = nil }},
+	"stats":          {drop: func(r *AnalysisResult) { r.Stats = nil }},
+	"api":            {drop: func(r *AnalysisResult) { r.API = nil }},
+	"lspSymbols":     {drop: func(r *AnalysisResult) { r.LSPSymbols = nil }},
+	"packageImports": {drop: func(r *AnalysisResult) { r.PackageImports = nil }},
+	"cycles": {
+		drop:  func(r *AnalysisResult) { r.Cycles = nil },
+		count: func(r AnalysisResult) int { return len(r.Cycles) },
+	},
+}
+
+// DefaultSections are reported when -sections is not given. The others
+// either cost extra loading or are more specialized reviews.
+var DefaultSections = []string{
+	"interfaces",
+	"structs",
+	"functions",
+	"leakyAPIs",
+	"uncalledInterfaces",
+	"interfaceHierarchy",
+	"errorTypes",
+	"deprecated",
+}
+
+// wants reports whether the analysis behind section should run.
+func (opts Options) wants(section string) bool {
+	return opts.Sections == nil || opts.Sections[section]
+}
+
+// ParseSections validates a comma-separated list of section names.
+func ParseSections(list string) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := sections[name]; !ok {
+			return nil, fmt.Errorf("unknown section %q", name)
+		}
+		selected[name] = true
+	}
+	return selected, nil
+}
+
+// dropSections removes the sections not selected from result. Dropped
+// sections are omitted from the output instead of showing up empty.
+func dropSections(result *AnalysisResult, selected map[string]bool) {
+	for name, section := range sections {
+		if !selected[name] {
+			section.drop(result)
+		}
+	}
+}
+
+// MarshalJSON leaves out the sections dropSections removed. Sections that
+// were computed are never nil, so they are still reported when empty.
+func (r AnalysisResult) MarshalJSON() ([]byte, error) {
+	type plain AnalysisResult
+	data, err := json.Marshal(plain(r))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		if string(value) == "null" {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
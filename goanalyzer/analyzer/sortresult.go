@@ -0,0 +1,176 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// sortResult puts the lists of result in an order that does not depend on
+// how packages were loaded, cached or scheduled, so that reports of the same
+// code compare equal byte for byte. Declarations are ordered by package
+// path, then name, then position, and findings by package path, then
+// position. The methods of interfaces and structs are ordered by name, as
+// are the interfaces a struct implements, after their package path. Lists
+// whose order carries meaning, such as fields, parameters, embedded types
+// and cycle paths, keep declaration order.
+func sortResult(result *AnalysisResult) {
+	sortInterfaces(result.Interfaces)
+	sortInterfaces(result.ExternalInterfaces)
+	sortStructs(result.Structs)
+	sort.SliceStable(result.Functions, func(i, j int) bool {
+		a, b := result.Functions[i], result.Functions[j]
+		return declarationLess(Declaration{Name: a.Name, Package: a.Package, Position: a.Position},
+			Declaration{Name: b.Name, Package: b.Package, Position: b.Position})
+	})
+	for i := range result.LocalTypes {
+		if iface := result.LocalTypes[i].Interface; iface != nil {
+			sortMethods(iface.Methods)
+		}
+		if strct := result.LocalTypes[i].Struct; strct != nil {
+			sortMethods(strct.Methods)
+			sortDeclarations(strct.ImplementedInterfaces)
+		}
+	}
+	sort.SliceStable(result.LocalTypes, func(i, j int) bool {
+		a, b := result.LocalTypes[i], result.LocalTypes[j]
+		if c := compareDeclarations(a.Function, b.Function); c != 0 {
+			return c < 0
+		}
+		return positionLess(localTypePosition(a), localTypePosition(b))
+	})
+
+	for _, decls := range [][]Declaration{
+		result.UncalledInterfaces,
+		result.ErrorTypes,
+		result.IgnoredContexts,
+		result.Deprecated,
+		result.Undocumented,
+	} {
+		sortDeclarations(decls)
+	}
+	sortBy(result.LeakyAPIs, func(leak LeakyAPI) Declaration { return leak.Declaration })
+	sort.SliceStable(result.InterfaceHierarchy, func(i, j int) bool {
+		a, b := result.InterfaceHierarchy[i], result.InterfaceHierarchy[j]
+		if c := compareDeclarations(a.From, b.From); c != 0 {
+			return c < 0
+		}
+		return declarationLess(a.To, b.To)
+	})
+	sort.SliceStable(result.RedundantMethods, func(i, j int) bool {
+		a, b := result.RedundantMethods[i], result.RedundantMethods[j]
+		if c := compareDeclarations(a.Interface, b.Interface); c != 0 {
+			return c < 0
+		}
+		return a.Method < b.Method
+	})
+	sortBy(result.ReinventedInterfaces, func(reinvented ReinventedInterface) Declaration { return reinvented.Interface })
+	sortBy(result.Enums, func(enum Enum) Declaration { return enum.Type })
+	sortBy(result.FluentTypes, func(fluent FluentType) Declaration { return fluent.Type })
+	sort.SliceStable(result.Decorators, func(i, j int) bool {
+		a, b := result.Decorators[i], result.Decorators[j]
+		if c := compareDeclarations(a.Struct, b.Struct); c != 0 {
+			return c < 0
+		}
+		return declarationLess(a.Interface, b.Interface)
+	})
+	sortBy(result.ExtractInterfaceCandidates, func(candidate ExtractInterfaceCandidate) Declaration { return candidate.Struct })
+	sortBy(result.TooManyParams, func(violation ParamViolation) Declaration { return violation.Declaration })
+	sortBy(result.NamingSuggestions, func(suggestion NamingSuggestion) Declaration { return suggestion.Interface })
+	sortBy(result.Examples, func(example ExampleInfo) Declaration {
+		return Declaration{Name: example.Name, Package: example.Package, Position: example.Position}
+	})
+
+	sortFindings(result.Conversions, func(c Conversion) (string, Position) { return c.Package, c.Position })
+	sortFindings(result.TypeAssertions, func(a TypeAssertion) (string, Position) { return a.Package, a.Position })
+	sortFindings(result.EmptyStructUses, func(u EmptyStructUse) (string, Position) { return u.Package, u.Position })
+	sortFindings(result.ValueCopyWarnings, func(w ValueCopyWarning) (string, Position) { return w.Package, w.Position })
+}
+
+func sortInterfaces(ifaces []InterfaceInfo) {
+	for i := range ifaces {
+		sortMethods(ifaces[i].Methods)
+	}
+	sortBy(ifaces, interfaceDeclaration)
+}
+
+func sortStructs(structs []StructInfo) {
+	for i := range structs {
+		sortMethods(structs[i].Methods)
+		sortDeclarations(structs[i].ImplementedInterfaces)
+	}
+	sortBy(structs, func(strct StructInfo) Declaration {
+		return Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position}
+	})
+}
+
+func sortMethods(methods []MethodInfo) {
+	for i := range methods {
+		sortDeclarations(methods[i].ImplementedFrom)
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		a, b := methods[i], methods[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return positionLess(a.Position, b.Position)
+	})
+}
+
+func sortDeclarations(decls []Declaration) {
+	sort.SliceStable(decls, func(i, j int) bool {
+		return declarationLess(decls[i], decls[j])
+	})
+}
+
+// sortBy orders items by the declaration each is about.
+func sortBy[T any](items []T, declaration func(T) Declaration) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return declarationLess(declaration(items[i]), declaration(items[j]))
+	})
+}
+
+// sortFindings orders items by the package and position of each.
+func sortFindings[T any](items []T, site func(T) (string, Position)) {
+	sort.SliceStable(items, func(i, j int) bool {
+		pkgI, posI := site(items[i])
+		pkgJ, posJ := site(items[j])
+		if pkgI != pkgJ {
+			return pkgI < pkgJ
+		}
+		return positionLess(posI, posJ)
+	})
+}
+
+func declarationLess(a, b Declaration) bool {
+	return compareDeclarations(a, b) < 0
+}
+
+// compareDeclarations orders declarations by package path, name and
+// position, and the instantiations of a generic interface by their type
+// arguments.
+func compareDeclarations(a, b Declaration) int {
+	if c := strings.Compare(a.Package, b.Package); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	if positionLess(a.Position, b.Position) {
+		return -1
+	}
+	if positionLess(b.Position, a.Position) {
+		return 1
+	}
+	return slices.Compare(a.TypeArguments, b.TypeArguments)
+}
+
+func localTypePosition(local LocalType) Position {
+	if local.Interface != nil {
+		return local.Interface.Position
+	}
+	if local.Struct != nil {
+		return local.Struct.Position
+	}
+	return Position{}
+}
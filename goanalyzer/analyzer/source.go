@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// declarationSources holds the source text of type, function and method
+// declarations, keyed by the position of the declared name. Types and
+// functions are kept apart, as a one-line interface declares a type and
+// methods on the same line.
+type declarationSources struct {
+	types map[Position]string
+	funcs map[Position]string
+}
+
+// collectSources reads the source text of every type, function, method and
+// interface method declaration in pkgs. Texts longer than maxLines lines are cut,
+// keeping at least the first line, which holds the signature; 0 keeps them
+// whole. Sources are read from overlay where present, else from disk.
+func collectSources(pkgs []*packages.Package, overlay map[string][]byte, maxLines int) *declarationSources {
+	decls := &declarationSources{
+		types: make(map[Position]string),
+		funcs: make(map[Position]string),
+	}
+	files := newSourceFiles(overlay)
+
+	add := func(into map[Position]string, fset *token.FileSet, name *ast.Ident, node ast.Node) {
+		namePos := fset.Position(name.Pos())
+		key := Position{Path: makeRelativePath(namePos.Filename), Line: namePos.Line}
+		if _, ok := into[key]; ok {
+			return
+		}
+		src := files.get(namePos.Filename)
+		start, end := fset.Position(node.Pos()).Offset, fset.Position(node.End()).Offset
+		if src == nil || end > len(src) {
+			return
+		}
+		into[key] = truncateSource(string(src[start:end]), maxLines)
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					add(decls.funcs, pkg.Fset, decl.Name, decl)
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						typeSpec, ok := spec.(*ast.TypeSpec)
+						if !ok {
+							continue
+						}
+
+						// An unparenthesized declaration starts at the
+						// "type" keyword.
+						var node ast.Node = typeSpec
+						if !decl.Lparen.IsValid() {
+							node = decl
+						}
+						add(decls.types, pkg.Fset, typeSpec.Name, node)
+
+						if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+							for _, field := range iface.Methods.List {
+								for _, name := range field.Names {
+									add(decls.funcs, pkg.Fset, name, field)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// truncateSource cuts text to maxLines lines, noting how many it had.
+func truncateSource(text string, maxLines int) string {
+	lines := strings.Split(text, "\n")
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return text
+	}
+	return strings.Join(lines[:maxLines], "\n") + fmt.Sprintf("\n// ... truncated, %d of %d lines shown", maxLines, len(lines))
+}
+
+func (decls *declarationSources) addToMethods(methods []MethodInfo) {
+	for i := range methods {
+		methods[i].Source = decls.funcs[methods[i].Position.line()]
+	}
+}
@@ -0,0 +1,304 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var formatExtensions = map[string]string{
+	"json":        ".json",
+	"api":         ".json",
+	"toml":        ".toml",
+	"cypher":      ".cypher",
+	"text":        ".txt",
+	"index":       ".json",
+	"fingerprint": ".json",
+	"lsp-symbols": ".json",
+	"deps-dot":    ".dot",
+	"dot":         ".dot",
+	"mermaid":     ".mmd",
+	"plantuml":    ".puml",
+	"sarif":       ".sarif",
+}
+
+type packageResult struct {
+	Path   string
+	Result AnalysisResult
+}
+
+// splitByPackage divides result into one result per package, ordered by
+// package path. Errors are attributed by their "package <path>:" prefix.
+func splitByPackage(result AnalysisResult) []packageResult {
+	parts := make(map[string]*AnalysisResult)
+	part := func(pkg string) *AnalysisResult {
+		if r, ok := parts[pkg]; ok {
+			return r
+		}
+		r := &AnalysisResult{
+			SchemaVersion:              result.SchemaVersion,
+			GeneratorVersion:           result.GeneratorVersion,
+			Revision:                   result.Revision,
+			Interfaces:                 make([]InterfaceInfo, 0),
+			Structs:                    make([]StructInfo, 0),
+			Functions:                  make([]FunctionInfo, 0),
+			LeakyAPIs:                  make([]LeakyAPI, 0),
+			UncalledInterfaces:         make([]Declaration, 0),
+			InterfaceHierarchy:         make([]InterfaceEdge, 0),
+			ReinventedInterfaces:       make([]ReinventedInterface, 0),
+			ErrorTypes:                 make([]Declaration, 0),
+			FluentTypes:                make([]FluentType, 0),
+			Decorators:                 make([]Decorator, 0),
+			ExtractInterfaceCandidates: make([]ExtractInterfaceCandidate, 0),
+			IgnoredContexts:            make([]Declaration, 0),
+			Conversions:                make([]Conversion, 0),
+			TypeAssertions:             make([]TypeAssertion, 0),
+			EmptyStructUses:            make([]EmptyStructUse, 0),
+			ValueCopyWarnings:          make([]ValueCopyWarning, 0),
+			Enums:                      make([]Enum, 0),
+			Instantiations:             make([]Instantiation, 0),
+			Modules:                    make([]ModuleInfo, 0),
+			RedundantMethods:           make([]RedundantMethod, 0),
+			Deprecated:                 make([]Declaration, 0),
+			Undocumented:               make([]Declaration, 0),
+			Errors:                     make([]string, 0),
+		}
+		parts[pkg] = r
+		return r
+	}
+
+	for _, iface := range result.Interfaces {
+		p := part(iface.Package)
+		p.Interfaces = append(p.Interfaces, iface)
+	}
+	for _, strct := range result.Structs {
+		p := part(strct.Package)
+		p.Structs = append(p.Structs, strct)
+	}
+	for _, fn := range result.Functions {
+		p := part(fn.Package)
+		p.Functions = append(p.Functions, fn)
+	}
+	// External interfaces go with the packages whose structs implement them.
+	for _, iface := range result.ExternalInterfaces {
+		for _, p := range parts {
+		structs:
+			for _, strct := range p.Structs {
+				for _, implemented := range strct.ImplementedInterfaces {
+					if implemented.Package == iface.Package && implemented.Name == iface.Name {
+						p.ExternalInterfaces = append(p.ExternalInterfaces, iface)
+						break structs
+					}
+				}
+			}
+		}
+	}
+	for _, leak := range result.LeakyAPIs {
+		p := part(leak.Declaration.Package)
+		p.LeakyAPIs = append(p.LeakyAPIs, leak)
+	}
+	for _, decl := range result.UncalledInterfaces {
+		p := part(decl.Package)
+		p.UncalledInterfaces = append(p.UncalledInterfaces, decl)
+	}
+	for _, edge := range result.InterfaceHierarchy {
+		p := part(edge.From.Package)
+		p.InterfaceHierarchy = append(p.InterfaceHierarchy, edge)
+	}
+	for _, redundant := range result.RedundantMethods {
+		p := part(redundant.Interface.Package)
+		p.RedundantMethods = append(p.RedundantMethods, redundant)
+	}
+	for _, reinvented := range result.ReinventedInterfaces {
+		p := part(reinvented.Interface.Package)
+		p.ReinventedInterfaces = append(p.ReinventedInterfaces, reinvented)
+	}
+	for _, decl := range result.ErrorTypes {
+		p := part(decl.Package)
+		p.ErrorTypes = append(p.ErrorTypes, decl)
+	}
+	for _, fluent := range result.FluentTypes {
+		p := part(fluent.Type.Package)
+		p.FluentTypes = append(p.FluentTypes, fluent)
+	}
+	for _, decorator := range result.Decorators {
+		p := part(decorator.Struct.Package)
+		p.Decorators = append(p.Decorators, decorator)
+	}
+	for _, candidate := range result.ExtractInterfaceCandidates {
+		p := part(candidate.Struct.Package)
+		p.ExtractInterfaceCandidates = append(p.ExtractInterfaceCandidates, candidate)
+	}
+	for _, decl := range result.IgnoredContexts {
+		p := part(decl.Package)
+		p.IgnoredContexts = append(p.IgnoredContexts, decl)
+	}
+	for _, conversion := range result.Conversions {
+		p := part(conversion.Package)
+		p.Conversions = append(p.Conversions, conversion)
+	}
+	for _, assertion := range result.TypeAssertions {
+		p := part(assertion.Package)
+		p.TypeAssertions = append(p.TypeAssertions, assertion)
+	}
+	for _, use := range result.EmptyStructUses {
+		p := part(use.Package)
+		p.EmptyStructUses = append(p.EmptyStructUses, use)
+	}
+	for _, warning := range result.ValueCopyWarnings {
+		p := part(warning.Package)
+		p.ValueCopyWarnings = append(p.ValueCopyWarnings, warning)
+	}
+	for _, enum := range result.Enums {
+		p := part(enum.Type.Package)
+		p.Enums = append(p.Enums, enum)
+	}
+	// Instantiations go with the packages using them.
+	for _, inst := range result.Instantiations {
+		for _, instance := range inst.Instances {
+			for _, pkg := range instance.Packages {
+				p := part(pkg)
+				if n := len(p.Instantiations); n == 0 || p.Instantiations[n-1].Generic.Position != inst.Generic.Position {
+					p.Instantiations = append(p.Instantiations, Instantiation{Generic: inst.Generic})
+				}
+				last := &p.Instantiations[len(p.Instantiations)-1]
+				last.Instances = append(last.Instances, Instance{ID: instance.ID, TypeArguments: instance.TypeArguments, Packages: []string{pkg}})
+			}
+		}
+	}
+	for _, decl := range result.Deprecated {
+		p := part(decl.Package)
+		p.Deprecated = append(p.Deprecated, decl)
+	}
+	for _, decl := range result.Undocumented {
+		p := part(decl.Package)
+		p.Undocumented = append(p.Undocumented, decl)
+	}
+	for _, example := range result.Examples {
+		p := part(example.Package)
+		p.Examples = append(p.Examples, example)
+	}
+	for _, violation := range result.TooManyParams {
+		p := part(violation.Declaration.Package)
+		p.TooManyParams = append(p.TooManyParams, violation)
+	}
+	for _, suggestion := range result.NamingSuggestions {
+		p := part(suggestion.Interface.Package)
+		p.NamingSuggestions = append(p.NamingSuggestions, suggestion)
+	}
+	for _, edge := range result.CallGraph {
+		p := part(edge.Package)
+		p.CallGraph = append(p.CallGraph, edge)
+	}
+	for _, local := range result.LocalTypes {
+		p := part(local.Function.Package)
+		p.LocalTypes = append(p.LocalTypes, local)
+	}
+	for _, document := range result.LSPSymbols {
+		p := part(document.Package)
+		p.LSPSymbols = append(p.LSPSymbols, document)
+	}
+	for _, node := range result.PackageImports {
+		p := part(node.Path)
+		p.PackageImports = append(p.PackageImports, node)
+	}
+	for _, cycle := range result.Cycles {
+		p := part(cycle.Package)
+		p.Cycles = append(p.Cycles, cycle)
+	}
+	for _, api := range result.API {
+		p := part(api.Path)
+		p.API = append(p.API, api)
+	}
+	for _, msg := range result.Errors {
+		if rest, ok := strings.CutPrefix(msg, "package "); ok {
+			if pkg, _, ok := strings.Cut(rest, ":"); ok {
+				p := part(pkg)
+				p.Errors = append(p.Errors, msg)
+			}
+		}
+	}
+
+	// Each package goes with the module providing it.
+	for pkg, p := range parts {
+		var provider *ModuleInfo
+		for i, module := range result.Modules {
+			if (pkg == module.Path || strings.HasPrefix(pkg, module.Path+"/")) && (provider == nil || len(module.Path) > len(provider.Path)) {
+				provider = &result.Modules[i]
+			}
+		}
+		if provider != nil {
+			p.Modules = append(p.Modules, *provider)
+		}
+	}
+
+	split := make([]packageResult, 0, len(parts))
+	for path, r := range parts {
+		split = append(split, packageResult{Path: path, Result: *r})
+	}
+	sort.Slice(split, func(i, j int) bool {
+		return split[i].Path < split[j].Path
+	})
+	return split
+}
+
+// packageFileName turns an import path into a flat, portable file name, e.g.
+// "example.com/app/internal/store" becomes "example.com_app_internal_store".
+// Distinct paths can map to the same name, such as "a/b" and "a_b"; see
+// uniqueFileName.
+func packageFileName(pkgPath string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, pkgPath)
+	name = strings.Trim(name, ".")
+	if name == "" {
+		name = "_"
+	}
+	return name
+}
+
+// uniqueFileName returns name, or name with the first suffix "-2", "-3" and
+// so on that makes it unique among used, and adds the result to used. Names
+// are compared ignoring case, as file systems may.
+func uniqueFileName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		unique = name + "-" + strconv.Itoa(i)
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+// writeSplit writes one file per package into dir in the given format.
+// Packages whose file names collide get numbered suffixes in path order.
+func writeSplit(dir string, parts []packageResult, format string, opts Options) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	used := make(map[string]bool)
+	for _, part := range parts {
+		filename := filepath.Join(dir, uniqueFileName(packageFileName(part.Path), used)+formatExtensions[format])
+		file, err := os.Create(filename)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", filename, err)
+		}
+
+		err = writeResult(file, part.Result, format, opts)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", filename, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitFileNamesUnique(t *testing.T) {
+	paths := []string{"a/b", "a/b-2", "a_b", "example.com/App", "example.com/app", "example.com_app"}
+	want := []string{"a_b", "a_b-2", "a_b-3", "example.com_App", "example.com_app-2", "example.com_app-3"}
+	used := make(map[string]bool)
+	var got []string
+	for _, path := range paths {
+		got = append(got, uniqueFileName(packageFileName(path), used))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("file names = %q, want %q", got, want)
+	}
+}
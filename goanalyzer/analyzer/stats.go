@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Stats struct {
+	Interfaces int `json:"interfaces"`
+	Structs    int `json:"structs"`
+	Methods    int `json:"methods"`
+	// EmptyStructs counts the structs without fields and EmptyStructUses
+	// the map and channel types with empty struct elements.
+	EmptyStructs    int `json:"emptyStructs"`
+	EmptyStructUses int `json:"emptyStructUses"`
+	// Histograms map a parameter or return count to the number of methods
+	// declaring that many.
+	ParamHistogram  map[int]int `json:"paramHistogram"`
+	ReturnHistogram map[int]int `json:"returnHistogram"`
+	// InterfaceRanking orders interfaces by number of implementing structs
+	// and StructRanking orders structs by number of implemented interfaces.
+	InterfaceRanking []RankedDeclaration `json:"interfaceRanking"`
+	StructRanking    []RankedDeclaration `json:"structRanking"`
+	// ParamTotalRanking orders types by the parameters of their declared
+	// methods, EmbeddingRanking structs by embedding depth and FieldRanking
+	// structs by field count.
+	ParamTotalRanking []RankedDeclaration `json:"paramTotalRanking"`
+	EmbeddingRanking  []RankedDeclaration `json:"embeddingRanking"`
+	FieldRanking      []RankedDeclaration `json:"fieldRanking"`
+	// DocCoverage is the percentage of exported interfaces, structs and
+	// methods with a doc comment, when the undocumented section is reported.
+	DocCoverage *float64 `json:"docCoverage,omitempty"`
+	// TokenRanking orders methods by the number of tokens in their body,
+	// when -token-counts is set.
+	TokenRanking []RankedDeclaration `json:"tokenRanking,omitempty"`
+}
+
+type RankedDeclaration struct {
+	Declaration Declaration `json:"declaration"`
+	Count       int         `json:"count"`
+}
+
+type ParamViolation struct {
+	Declaration Declaration `json:"declaration"`
+	ParamCount  int         `json:"paramCount"`
+}
+
+// forEachMethod calls fn once per declared method in result. Methods promoted
+// into several structs are visited only at their declaration.
+func forEachMethod(result AnalysisResult, fn func(owner string, pkg string, method MethodInfo)) {
+	seen := make(map[string]bool)
+	visit := func(owner, pkg string, methods []MethodInfo) {
+		for _, method := range methods {
+			key := fmt.Sprintf("%s:%d:%s", method.Position.Path, method.Position.Line, method.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fn(owner, pkg, method)
+		}
+	}
+
+	for _, iface := range result.Interfaces {
+		visit(iface.Name, iface.Package, iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		visit(strct.Name, strct.Package, strct.Methods)
+	}
+}
+
+// computeStats summarizes result. Rankings are cut to the top entries unless
+// top is 0.
+func computeStats(result AnalysisResult, top int) *Stats {
+	stats := &Stats{
+		Interfaces:      len(result.Interfaces),
+		Structs:         len(result.Structs),
+		EmptyStructUses: len(result.EmptyStructUses),
+		ParamHistogram:  make(map[int]int),
+		ReturnHistogram: make(map[int]int),
+	}
+
+	forEachMethod(result, func(owner string, pkg string, method MethodInfo) {
+		stats.Methods++
+		stats.ParamHistogram[method.ParamCount]++
+		stats.ReturnHistogram[method.ReturnCount]++
+		if method.TokenCount > 0 {
+			stats.TokenRanking = append(stats.TokenRanking, RankedDeclaration{
+				Declaration: Declaration{Name: owner + "." + method.Name, Package: pkg, Position: method.Position},
+				Count:       method.TokenCount,
+			})
+		}
+	})
+
+	implementers := make(map[string]int)
+	stats.StructRanking = make([]RankedDeclaration, 0, len(result.Structs))
+	for _, strct := range result.Structs {
+		if strct.IsEmpty {
+			stats.EmptyStructs++
+		}
+		for _, iface := range strct.ImplementedInterfaces {
+			implementers[iface.Package+"."+iface.Name]++
+		}
+		stats.StructRanking = append(stats.StructRanking, RankedDeclaration{
+			Declaration: Declaration{Name: strct.Name, Package: strct.Package, Position: strct.Position},
+			Count:       len(strct.ImplementedInterfaces),
+		})
+	}
+
+	stats.InterfaceRanking = make([]RankedDeclaration, 0, len(result.Interfaces))
+	for _, iface := range result.Interfaces {
+		stats.InterfaceRanking = append(stats.InterfaceRanking, RankedDeclaration{
+			Declaration: interfaceDeclaration(iface),
+			Count:       implementers[iface.Package+"."+iface.Name],
+		})
+	}
+
+	if coverage, ok := docCoverage(result); ok {
+		stats.DocCoverage = &coverage
+	}
+
+	stats.InterfaceRanking = rank(stats.InterfaceRanking, top)
+	stats.StructRanking = rank(stats.StructRanking, top)
+	stats.TokenRanking = rank(stats.TokenRanking, top)
+	stats.ParamTotalRanking, stats.EmbeddingRanking, stats.FieldRanking = complexityRankings(result, top)
+	return stats
+}
+
+// rank sorts by descending count, breaking ties by qualified name, and keeps
+// the first top entries.
+func rank(ranking []RankedDeclaration, top int) []RankedDeclaration {
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Count != ranking[j].Count {
+			return ranking[i].Count > ranking[j].Count
+		}
+		a, b := ranking[i].Declaration, ranking[j].Declaration
+		return a.Package+"."+a.Name < b.Package+"."+b.Name
+	})
+	if top > 0 && len(ranking) > top {
+		ranking = ranking[:top]
+	}
+	return ranking
+}
+
+func findTooManyParams(result AnalysisResult, max int) []ParamViolation {
+	violations := make([]ParamViolation, 0)
+	forEachMethod(result, func(owner string, pkg string, method MethodInfo) {
+		if method.ParamCount <= max {
+			return
+		}
+		violations = append(violations, ParamViolation{
+			Declaration: Declaration{
+				Name:     owner + "." + method.Name,
+				Package:  pkg,
+				Position: method.Position,
+			},
+			ParamCount: method.ParamCount,
+		})
+	})
+	for _, fn := range result.Functions {
+		if fn.ParamCount > max {
+			violations = append(violations, ParamViolation{
+				Declaration: Declaration{Name: fn.Name, Package: fn.Package, Position: fn.Position},
+				ParamCount:  fn.ParamCount,
+			})
+		}
+	}
+	return violations
+}
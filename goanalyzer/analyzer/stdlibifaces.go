@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultStdlibInterfaces are the standard library interfaces most often
+// implemented on purpose.
+var DefaultStdlibInterfaces = []string{
+	"error",
+	"fmt.Stringer",
+	"fmt.Formatter",
+	"io.Reader",
+	"io.Writer",
+	"io.Closer",
+	"io.ReaderFrom",
+	"io.WriterTo",
+	"sort.Interface",
+	"encoding.TextMarshaler",
+	"encoding.TextUnmarshaler",
+	"encoding/json.Marshaler",
+	"encoding/json.Unmarshaler",
+	"database/sql.Scanner",
+	"database/sql/driver.Valuer",
+	"net/http.Handler",
+	"flag.Value",
+}
+
+// loadStdlibInterfaces resolves names such as "io.Reader", or "error", to
+// the interfaces they name. They are looked up in the packages the analyzed
+// code imports first, so that methods mentioning their types match. Those
+// packages only hold the declarations the analyzed code needs, so the
+// interfaces missing there are loaded on their own.
+func loadStdlibInterfaces(ctx context.Context, names []string, pkgs []*packages.Package, src *sourceIndex, style typeStyle) ([]externalInterface, error) {
+	imported := make(map[string]*types.Package)
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if imported[pkg.Path()] != nil {
+			return
+		}
+		imported[pkg.Path()] = pkg
+		for _, dep := range pkg.Imports() {
+			visit(dep)
+		}
+	}
+	for _, pkg := range pkgs {
+		if pkg.Types != nil {
+			visit(pkg.Types)
+		}
+	}
+
+	lookup := func(pkg *types.Package, name string) types.Object {
+		if pkg == nil {
+			return nil
+		}
+		return pkg.Scope().Lookup(name)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if dot := strings.LastIndex(name, "."); dot > 0 && lookup(imported[name[:dot]], name[dot+1:]) == nil && !slices.Contains(missing, name[:dot]) {
+			missing = append(missing, name[:dot])
+		}
+	}
+	var fset *token.FileSet
+	if len(pkgs) > 0 {
+		fset = pkgs[0].Fset
+	}
+	loaded := make(map[string]*packages.Package)
+	if len(missing) > 0 {
+		cfg := &packages.Config{
+			Mode:    packages.NeedName | packages.NeedTypes,
+			Context: ctx,
+		}
+		found, err := packages.Load(cfg, missing...)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range found {
+			loaded[pkg.PkgPath] = pkg
+		}
+	}
+
+	resolved := make([]externalInterface, 0, len(names))
+	for _, name := range names {
+		var obj types.Object
+		declaring := &packages.Package{Fset: fset}
+		if dot := strings.LastIndex(name, "."); dot <= 0 {
+			obj = types.Universe.Lookup(name)
+		} else if obj = lookup(imported[name[:dot]], name[dot+1:]); obj != nil {
+			declaring.PkgPath = name[:dot]
+		} else if pkg := loaded[name[:dot]]; pkg != nil && pkg.Types != nil {
+			obj = pkg.Types.Scope().Lookup(name[dot+1:])
+			declaring.PkgPath = pkg.PkgPath
+			declaring.Fset = pkg.Fset
+		} else {
+			return nil, fmt.Errorf("package %s not found for interface %s", name[:dot], name)
+		}
+
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("interface %s not found", name)
+		}
+		iface, ok := typeName.Type().Underlying().(*types.Interface)
+		if named, isNamed := typeName.Type().(*types.Named); !ok || (isNamed && named.TypeParams().Len() > 0) {
+			return nil, fmt.Errorf("%s is not a non-generic interface", name)
+		}
+		if info := processInterface(typeName, declaring, src, style); info != nil {
+			resolved = append(resolved, externalInterface{iface: iface, info: *info})
+		}
+	}
+	return resolved, nil
+}
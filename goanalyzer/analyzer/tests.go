@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// isTestPackage reports whether pkg is a test variant holding _test.go
+// files: "p [p.test]", which adds them to p, or the external test package
+// "p_test [p.test]". Variants of other packages recompiled for p's tests
+// hold none, and are ruled out by the file names alone.
+func isTestPackage(pkg *packages.Package) bool {
+	return strings.Contains(pkg.ID, " [")
+}
+
+// findTestTypes returns the interfaces and structs declared in the _test.go
+// files of the test variant pkg, marked InTest, with the interface types
+// parallel to the interfaces.
+func findTestTypes(pkg *packages.Package, interfaces []indexedInterface, src *sourceIndex, cache *typeCache, style typeStyle, skip func(types.Object) bool) ([]InterfaceInfo, []*types.Interface, []StructInfo) {
+	ifaces := make([]InterfaceInfo, 0)
+	ifaceTypes := make([]*types.Interface, 0)
+	structs := make([]StructInfo, 0)
+	interfaces = testInterfaces(interfaces, pkg)
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() || skip(obj) || !strings.HasSuffix(pkg.Fset.Position(obj.Pos()).Filename, "_test.go") {
+			continue
+		}
+		switch t := obj.Type().Underlying().(type) {
+		case *types.Interface:
+			if t.NumMethods() == 0 {
+				continue
+			}
+			if iface := processInterface(obj, pkg, src, style); iface != nil {
+				iface.InTest = true
+				ifaces = append(ifaces, *iface)
+				ifaceTypes = append(ifaceTypes, t)
+			}
+		case *types.Struct:
+			if strct := processStruct(obj, pkg, interfaces, src, cache, style); strct != nil {
+				strct.InTest = true
+				structs = append(structs, *strct)
+			}
+		}
+	}
+	return ifaces, ifaceTypes, structs
+}
+
+// testInterfaces returns interfaces as the test variant pkg sees them. The
+// test binary recompiles the package under test, and the packages between
+// it and its tests, with types of their own, which the types of their
+// regular builds are not identical to.
+func testInterfaces(interfaces []indexedInterface, pkg *packages.Package) []indexedInterface {
+	view := make(map[string]*types.Package)
+	var visit func(tpkg *types.Package)
+	visit = func(tpkg *types.Package) {
+		if _, ok := view[tpkg.Path()]; ok {
+			return
+		}
+		view[tpkg.Path()] = tpkg
+		for _, imported := range tpkg.Imports() {
+			visit(imported)
+		}
+	}
+	visit(pkg.Types)
+
+	rebased := make([]indexedInterface, len(interfaces))
+	for i, indexed := range interfaces {
+		if tpkg := view[indexed.info.Package]; tpkg != nil {
+			if obj, ok := tpkg.Scope().Lookup(indexed.info.Name).(*types.TypeName); ok {
+				if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+					indexed.iface = iface
+					if indexed.generic != nil {
+						indexed.generic, _ = obj.Type().(*types.Named)
+					}
+				}
+			}
+		}
+		rebased[i] = indexed
+	}
+	return rebased
+}
@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// writeText prints one line per interface and struct, e.g.
+//
+//	interface interfaces.Repository (5 methods) @ internal/interfaces/repository.go:3
+//	struct repositories.UserPostgresRepository implements Repository @ internal/repositories/user_repository.go:8
+func writeText(w io.Writer, result AnalysisResult) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# schemaVersion %s, generatorVersion %s\n", result.SchemaVersion, result.GeneratorVersion)
+
+	for _, iface := range result.Interfaces {
+		fmt.Fprintf(bw, "interface %s.%s (%d methods)%s\n",
+			path.Base(iface.Package), iface.Name, len(iface.Methods), atPosition(iface.Position))
+	}
+
+	for _, strct := range result.Structs {
+		line := fmt.Sprintf("struct %s.%s", path.Base(strct.Package), strct.Name)
+		if len(strct.ImplementedInterfaces) > 0 {
+			names := make([]string, 0, len(strct.ImplementedInterfaces))
+			for _, iface := range strct.ImplementedInterfaces {
+				names = append(names, iface.Name)
+			}
+			line += " implements " + strings.Join(names, ", ")
+		}
+		fmt.Fprintf(bw, "%s%s\n", line, atPosition(strct.Position))
+	}
+
+	return bw.Flush()
+}
+
+// atPosition renders pos as " @ path:line", or returns "" for declarations
+// without a position.
+func atPosition(pos Position) string {
+	if pos.Path == "" {
+		return ""
+	}
+	return fmt.Sprintf(" @ %s:%d", pos.Path, pos.Line)
+}
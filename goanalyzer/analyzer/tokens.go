@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/scanner"
+	"go/token"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// collectTokenCounts counts the tokens in every function and method body,
+// keyed by the position of the declaring function's name. Unlike line
+// counts, this ignores comments, blank lines and formatting, so it is a
+// steadier measure of how much code a method holds. The braces around the
+// body are included. Sources are read from overlay where present, else from
+// disk.
+func collectTokenCounts(pkgs []*packages.Package, overlay map[string][]byte) map[Position]int {
+	counts := make(map[Position]int)
+	sources := newSourceFiles(overlay)
+	forEachFuncBody(pkgs, func(pkg *packages.Package, key Position, decl *ast.FuncDecl) {
+		file := pkg.Fset.File(decl.Body.Pos())
+		if file == nil {
+			return
+		}
+		src := sources.get(file.Name())
+		start, end := file.Offset(decl.Body.Lbrace), file.Offset(decl.Body.Rbrace)+1
+		if src == nil || end > len(src) || src[start] != '{' {
+			return
+		}
+
+		var s scanner.Scanner
+		fset := token.NewFileSet()
+		s.Init(fset.AddFile("", -1, end-start), src[start:end], nil, 0)
+		count := 0
+		for {
+			_, tok, lit := s.Scan()
+			if tok == token.EOF {
+				break
+			}
+			// Automatically inserted semicolons are not in the source.
+			if tok == token.SEMICOLON && lit == "\n" {
+				continue
+			}
+			count++
+		}
+		counts[key] = count
+	})
+	return counts
+}
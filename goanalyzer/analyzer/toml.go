@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+// writeTOML encodes v as TOML with the same keys as its JSON form. Going
+// through the JSON representation keeps a single set of struct tags, and
+// nested slices of structs become arrays of tables.
+func writeTOML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	return toml.NewEncoder(w).Encode(tomlValue(doc))
+}
+
+// tomlValue converts decoded JSON into values TOML can represent: numbers
+// regain their integer type and nulls, which TOML has no notation for, are
+// dropped.
+func tomlValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		table := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if value != nil {
+				table[key] = tomlValue(value)
+			}
+		}
+		return table
+	case []interface{}:
+		if len(v) > 0 {
+			if _, ok := v[0].(map[string]interface{}); ok {
+				tables := make([]map[string]interface{}, 0, len(v))
+				for _, value := range v {
+					tables = append(tables, tomlValue(value).(map[string]interface{}))
+				}
+				return tables
+			}
+		}
+		values := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			if value != nil {
+				values = append(values, tomlValue(value))
+			}
+		}
+		return values
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"go/types"
+)
+
+// TypeRef is a structured form of a type, for consumers that need more than
+// the types.TypeString rendering. Named types are not expanded, so a TypeRef
+// is always finite even for recursive types.
+type TypeRef struct {
+	// Kind is one of basic, named, typeParam, pointer, slice, array, map,
+	// chan, func, struct or interface.
+	Kind    string `json:"kind"`
+	Package string `json:"package,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Len is the length of an array type.
+	Len int64 `json:"len,omitempty"`
+	// Dir is the direction of a channel type: both, send or recv.
+	Dir      string     `json:"dir,omitempty"`
+	Key      *TypeRef   `json:"key,omitempty"`
+	Elem     *TypeRef   `json:"elem,omitempty"`
+	TypeArgs []*TypeRef `json:"typeArgs,omitempty"`
+	Params   []*TypeRef `json:"params,omitempty"`
+	Results  []*TypeRef `json:"results,omitempty"`
+	Variadic bool       `json:"variadic,omitempty"`
+}
+
+func newTypeRef(t types.Type) *TypeRef {
+	switch t := t.(type) {
+	case *types.Basic:
+		return &TypeRef{Kind: "basic", Name: t.Name()}
+	case *types.Named:
+		ref := &TypeRef{Kind: "named", Name: t.Obj().Name()}
+		if pkg := t.Obj().Pkg(); pkg != nil {
+			ref.Package = pkg.Path()
+		}
+		if args := t.TypeArgs(); args != nil {
+			for i := 0; i < args.Len(); i++ {
+				ref.TypeArgs = append(ref.TypeArgs, newTypeRef(args.At(i)))
+			}
+		}
+		return ref
+	case *types.TypeParam:
+		return &TypeRef{Kind: "typeParam", Name: t.Obj().Name()}
+	case *types.Pointer:
+		return &TypeRef{Kind: "pointer", Elem: newTypeRef(t.Elem())}
+	case *types.Slice:
+		return &TypeRef{Kind: "slice", Elem: newTypeRef(t.Elem())}
+	case *types.Array:
+		return &TypeRef{Kind: "array", Len: t.Len(), Elem: newTypeRef(t.Elem())}
+	case *types.Map:
+		return &TypeRef{Kind: "map", Key: newTypeRef(t.Key()), Elem: newTypeRef(t.Elem())}
+	case *types.Chan:
+		dir := "both"
+		switch t.Dir() {
+		case types.SendOnly:
+			dir = "send"
+		case types.RecvOnly:
+			dir = "recv"
+		}
+		return &TypeRef{Kind: "chan", Dir: dir, Elem: newTypeRef(t.Elem())}
+	case *types.Signature:
+		return &TypeRef{
+			Kind:     "func",
+			Params:   tupleTypeRefs(t.Params()),
+			Results:  tupleTypeRefs(t.Results()),
+			Variadic: t.Variadic(),
+		}
+	case *types.Struct:
+		return &TypeRef{Kind: "struct"}
+	case *types.Interface:
+		return &TypeRef{Kind: "interface"}
+	default:
+		return &TypeRef{Kind: "basic", Name: types.TypeString(t, nil)}
+	}
+}
+
+func tupleTypeRefs(tuple *types.Tuple) []*TypeRef {
+	var refs []*TypeRef
+	for i := 0; i < tuple.Len(); i++ {
+		refs = append(refs, newTypeRef(tuple.At(i).Type()))
+	}
+	return refs
+}
+
+// addTypeRefs fills in the structured parameter and return types of methods
+// declared on, or promoted to, t.
+func addTypeRefs(methods []MethodInfo, t types.Type, pkg *types.Package) {
+	for i := range methods {
+		obj, _, _ := types.LookupFieldOrMethod(t, true, pkg, methods[i].Name)
+		method, ok := obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		signature := method.Type().(*types.Signature)
+		for j := range methods[i].Parameters {
+			methods[i].Parameters[j].TypeRef = newTypeRef(signature.Params().At(j).Type())
+		}
+		methods[i].ReturnTypeRefs = tupleTypeRefs(signature.Results())
+	}
+}
+
+// addConstraintRefs fills in the structured constraints of params, the
+// type parameters of t.
+func addConstraintRefs(params []TypeParam, t types.Type) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return
+	}
+	for i := range params {
+		params[i].ConstraintRef = newTypeRef(named.TypeParams().At(i).Constraint())
+	}
+}
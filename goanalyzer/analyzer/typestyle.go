@@ -0,0 +1,42 @@
+package analyzer
+
+import "go/types"
+
+// Type styles for -type-style, controlling how types name their package.
+const (
+	// typeStyleFull qualifies by import path: "example.com/app/store.User".
+	typeStyleFull = "full"
+	// typeStyleRelative leaves types from the package being described
+	// unqualified and qualifies the others by import path.
+	typeStyleRelative = "relative"
+	// typeStyleShort qualifies every type by package name: "store.User".
+	typeStyleShort = "short"
+)
+
+// TypeStyles lists the values Options.TypeStyle accepts besides empty,
+// which stands for relative.
+var TypeStyles = []string{typeStyleFull, typeStyleRelative, typeStyleShort}
+
+// typeStyle is the Options.TypeStyle of one analysis. It is passed to every
+// pass rendering types, so that analyses with different styles can run at
+// the same time.
+type typeStyle string
+
+// qualifier returns the types.Qualifier for rendering types in from.
+func (style typeStyle) qualifier(from *types.Package) types.Qualifier {
+	switch style {
+	case typeStyleFull:
+		return nil
+	case typeStyleShort:
+		return func(pkg *types.Package) string {
+			return pkg.Name()
+		}
+	default:
+		return types.RelativeTo(from)
+	}
+}
+
+// typeString renders t for a declaration in from.
+func (style typeStyle) typeString(t types.Type, from *types.Package) string {
+	return types.TypeString(t, style.qualifier(from))
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// collectInterfaceCalls records every interface method that pkg selects
+// through an interface-typed (or interface-constrained) value.
+func collectInterfaceCalls(pkg *packages.Package, called map[*types.Func]bool) {
+	for _, sel := range pkg.TypesInfo.Selections {
+		if sel.Kind() == types.FieldVal {
+			continue
+		}
+
+		fn, ok := sel.Obj().(*types.Func)
+		if !ok {
+			continue
+		}
+
+		recv := fn.Type().(*types.Signature).Recv()
+		if recv != nil && types.IsInterface(recv.Type()) {
+			called[fn.Origin()] = true
+		}
+	}
+}
+
+// findUncalledInterfaces returns the interfaces none of whose methods are
+// called through an interface value in the analyzed packages. It is
+// best-effort: calls from outside the analyzed module, via reflection or
+// through assignment to interfaces declared elsewhere are not seen.
+func findUncalledInterfaces(interfaces []InterfaceInfo, ifaceTypes []*types.Interface, called map[*types.Func]bool) []Declaration {
+	uncalled := make([]Declaration, 0)
+	for i, iface := range interfaces {
+		used := false
+		for j := 0; j < ifaceTypes[i].NumMethods(); j++ {
+			if called[ifaceTypes[i].Method(j).Origin()] {
+				used = true
+				break
+			}
+		}
+
+		if !used {
+			uncalled = append(uncalled, interfaceDeclaration(iface))
+		}
+	}
+	return uncalled
+}
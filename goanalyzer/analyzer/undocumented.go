@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findUndocumented returns the exported declarations in pkg the report
+// describes that lack a doc comment: interfaces with methods, structs, and
+// their exported methods. Methods interfaces embed and methods promoted
+// from embedded fields are documented where they are declared.
+func findUndocumented(pkg *packages.Package, src *sourceIndex, skip func(types.Object) bool) []Declaration {
+	undocumented := make([]Declaration, 0)
+	check := func(name string, obj types.Object) {
+		if _, ok := src.docs[obj.Pos()]; !ok {
+			decl := objectDeclaration(obj, pkg.Fset)
+			decl.Name = name
+			undocumented = append(undocumented, decl)
+		}
+	}
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || obj.IsAlias() || skip(obj) {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		switch t := named.Underlying().(type) {
+		case *types.Interface:
+			if t.NumMethods() == 0 {
+				continue
+			}
+			check(obj.Name(), obj)
+			for i := 0; i < t.NumExplicitMethods(); i++ {
+				if method := t.ExplicitMethod(i); method.Exported() {
+					check(obj.Name()+"."+method.Name(), method)
+				}
+			}
+		case *types.Struct:
+			check(obj.Name(), obj)
+			for i := 0; i < named.NumMethods(); i++ {
+				if method := named.Method(i); method.Exported() {
+					check(obj.Name()+"."+method.Name(), method)
+				}
+			}
+		}
+	}
+	return undocumented
+}
+
+// docCoverage returns the percentage of the documentable declarations in
+// result that have a doc comment, as findUndocumented counts them, and
+// whether there are any. It needs the undocumented section.
+func docCoverage(result AnalysisResult) (float64, bool) {
+	if result.Undocumented == nil {
+		return 0, false
+	}
+
+	documentable := make(map[string]bool)
+	add := func(pkg, name string, methods []MethodInfo) {
+		if !token.IsExported(name) {
+			return
+		}
+		documentable[pkg+"."+name] = true
+		for _, method := range methods {
+			if !method.Promoted && token.IsExported(method.Name) {
+				documentable[pkg+"."+name+"."+method.Name] = true
+			}
+		}
+	}
+	for _, iface := range result.Interfaces {
+		add(iface.Package, iface.Name, iface.Methods)
+	}
+	for _, strct := range result.Structs {
+		add(strct.Package, strct.Name, strct.Methods)
+	}
+	if len(documentable) == 0 {
+		return 0, false
+	}
+
+	undocumented := 0
+	for _, decl := range result.Undocumented {
+		if documentable[decl.Package+"."+decl.Name] {
+			undocumented++
+		}
+	}
+	return 100 * float64(len(documentable)-undocumented) / float64(len(documentable)), true
+}
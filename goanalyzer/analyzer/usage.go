@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// usageKinds are the ways an interface type can be used, in the order they
+// are reported.
+var usageKinds = []string{"parameter", "result", "field", "variable"}
+
+// interfaceUsage records the usage kinds of each named interface, keyed by
+// "package.Name" like the rankings in Stats.
+type interfaceUsage map[string]map[string]bool
+
+// collectInterfaceUsage records where pkg mentions named interface types: as
+// the type of a parameter, a result, a struct field or a variable. Pointers
+// to, and slices, arrays, maps and channels of, an interface count as well.
+// Parameters and results are those of functions, methods, function literals,
+// function types and interface methods alike.
+func collectInterfaceUsage(pkg *packages.Package, usage interfaceUsage, skipFile func(filename string) bool) {
+	record := func(t types.Type, kind string) {
+		if key, ok := usedInterface(t); ok {
+			if usage[key] == nil {
+				usage[key] = make(map[string]bool)
+			}
+			usage[key][kind] = true
+		}
+	}
+	recordFields := func(fields *ast.FieldList, kind string) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			record(pkg.TypesInfo.TypeOf(field.Type), kind)
+		}
+	}
+
+	// Parameter and field names are definitions too; remember them so that
+	// the remaining definitions are the variables.
+	notVariables := make(map[*ast.Ident]bool)
+	exclude := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				notVariables[name] = true
+			}
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename) {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				exclude(n.Recv)
+			case *ast.FuncType:
+				recordFields(n.Params, "parameter")
+				recordFields(n.Results, "result")
+				exclude(n.Params)
+				exclude(n.Results)
+			case *ast.StructType:
+				recordFields(n.Fields, "field")
+				exclude(n.Fields)
+			}
+			return true
+		})
+	}
+
+	for ident, obj := range pkg.TypesInfo.Defs {
+		v, ok := obj.(*types.Var)
+		if !ok || v.IsField() || notVariables[ident] || skipFile(pkg.Fset.Position(ident.Pos()).Filename) {
+			continue
+		}
+		record(v.Type(), "variable")
+	}
+}
+
+// usedInterface returns the key of the named interface t is, or holds.
+func usedInterface(t types.Type) (string, bool) {
+	for {
+		switch u := t.(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		case *types.Chan:
+			t = u.Elem()
+		case *types.Named:
+			obj := u.Origin().Obj()
+			if obj.Pkg() == nil || !types.IsInterface(u) {
+				return "", false
+			}
+			return obj.Pkg().Path() + "." + obj.Name(), true
+		default:
+			return "", false
+		}
+	}
+}
+
+// kinds returns the usage kinds recorded for iface, in usageKinds order.
+func (usage interfaceUsage) kinds(iface InterfaceInfo) []string {
+	var kinds []string
+	for _, kind := range usageKinds {
+		if usage[iface.Package+"."+iface.Name][kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"context"
+	"go/types"
+	"runtime"
+	"slices"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// packageAnalysis is what one package contributes to a result, kept apart
+// so that packages can be analyzed concurrently.
+type packageAnalysis struct {
+	// result holds only the entries the package adds.
+	result        AnalysisResult
+	module        *packages.Module
+	ifaceTypes    []*types.Interface
+	calledMethods map[*types.Func]bool
+	instances     instantiations
+	usage         interfaceUsage
+}
+
+func newPackageAnalysis() *packageAnalysis {
+	return &packageAnalysis{
+		calledMethods: make(map[*types.Func]bool),
+		instances:     make(instantiations),
+		usage:         make(interfaceUsage),
+	}
+}
+
+// mergeInto appends a's entries to those of the packages merged before.
+func (a *packageAnalysis) mergeInto(result *AnalysisResult, ifaceTypes *[]*types.Interface, calledMethods map[*types.Func]bool, instances instantiations, usage interfaceUsage) {
+	r := &a.result
+	result.Examples = append(result.Examples, r.Examples...)
+	result.PackageImports = append(result.PackageImports, r.PackageImports...)
+	result.Errors = append(result.Errors, r.Errors...)
+	packageSections{
+		Functions:         r.Functions,
+		LeakyAPIs:         r.LeakyAPIs,
+		Undocumented:      r.Undocumented,
+		IgnoredContexts:   r.IgnoredContexts,
+		Conversions:       r.Conversions,
+		TypeAssertions:    r.TypeAssertions,
+		EmptyStructUses:   r.EmptyStructUses,
+		ValueCopyWarnings: r.ValueCopyWarnings,
+		Enums:             r.Enums,
+		LSPSymbols:        r.LSPSymbols,
+		Cycles:            r.Cycles,
+	}.addTo(result)
+	result.API = append(result.API, r.API...)
+	result.Explained = append(result.Explained, r.Explained...)
+	result.Interfaces = append(result.Interfaces, r.Interfaces...)
+	result.FluentTypes = append(result.FluentTypes, r.FluentTypes...)
+	result.Structs = append(result.Structs, r.Structs...)
+	result.Decorators = append(result.Decorators, r.Decorators...)
+	result.ErrorTypes = append(result.ErrorTypes, r.ErrorTypes...)
+	result.LocalTypes = append(result.LocalTypes, r.LocalTypes...)
+
+	*ifaceTypes = append(*ifaceTypes, a.ifaceTypes...)
+	for fn := range a.calledMethods {
+		calledMethods[fn] = true
+	}
+	for obj, byArgs := range a.instances {
+		if instances[obj] == nil {
+			instances[obj] = make(map[string]*Instance)
+		}
+		for key, instance := range byArgs {
+			merged, ok := instances[obj][key]
+			if !ok {
+				instances[obj][key] = instance
+				continue
+			}
+			for _, pkg := range instance.Packages {
+				if !slices.Contains(merged.Packages, pkg) {
+					merged.Packages = append(merged.Packages, pkg)
+				}
+			}
+		}
+	}
+	for key, kinds := range a.usage {
+		if usage[key] == nil {
+			usage[key] = make(map[string]bool)
+		}
+		for kind := range kinds {
+			usage[key][kind] = true
+		}
+	}
+}
+
+// forEachPackage calls fn with the indexes 0 to n-1 from up to workers
+// goroutines, or GOMAXPROCS if workers is not positive. Indexes not yet
+// started once ctx is done are skipped. It returns when all calls have.
+func forEachPackage(ctx context.Context, n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
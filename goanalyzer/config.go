@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"goanalyzer/analyzer"
+)
+
+// ConfigFile is the name of the config file used when -config is not
+// given, looked up in -path and the directories above it.
+const ConfigFile = "goanalyzer.yaml"
+
+// Config is the schema of the -config file, e.g.
+//
+//	path: ./services
+//	include: [example.com/app/...]
+//	exclude: [example.com/app/internal/mocks/...]
+//	exportedOnly: true
+//	implements: [example.com/app/store.Store]
+//	sections: [interfaces, structs, stats]
+//	features: [goroutines]
+//	checks: [cycles]
+//	failOn: [leakyAPIs]
+//	format: sarif
+//
+// Settings given on the command line take precedence.
+type Config struct {
+	// Path is the root to analyze, relative to the config file.
+	Path string `yaml:"path"`
+	// Include and Exclude are import path patterns, as for -include and
+	// -exclude, which replace them.
+	Include      []string `yaml:"include"`
+	Exclude      []string `yaml:"exclude"`
+	ExportedOnly *bool    `yaml:"exportedOnly"`
+	// Implements only reports the structs implementing these interfaces,
+	// as -implements does.
+	Implements []string `yaml:"implements"`
+	// Sections lists the result sections to report, as -sections does.
+	Sections []string `yaml:"sections"`
+	// Features lists the optional analyses to enable, by the names in
+	// configFeatures.
+	Features []string `yaml:"features"`
+	// Checks and FailOn are the -check and -fail-on lists.
+	Checks []string `yaml:"checks"`
+	FailOn []string `yaml:"failOn"`
+	Format string   `yaml:"format"`
+}
+
+// configFeatures maps the feature names accepted in a config file to the
+// flags enabling them.
+var configFeatures = map[string]string{
+	"methodDependencies": "method-deps",
+	"examples":           "examples",
+	"tests":              "tests",
+	"goroutines":         "goroutines",
+	"panics":             "panics",
+	"tokenCounts":        "token-counts",
+	"returnedErrors":     "returned-errors",
+	"fieldAccess":        "field-access",
+	"source":             "include-source",
+	"stats":              "stats",
+	"structuredTypes":    "structured-types",
+	"namingLint":         "naming-lint",
+	"packageImports":     "package-imports",
+}
+
+// loadConfig reads and validates a config file. Unknown keys are errors, so
+// that typos do not silently change nothing.
+func loadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	if config.Format != "" && !analyzer.IsValidFormat(config.Format) {
+		return nil, fmt.Errorf("%s: invalid format %q: must be one of %s", filename, config.Format, strings.Join(analyzer.Formats, ", "))
+	}
+	if _, err := analyzer.ParseSections(strings.Join(config.Sections, ",")); err != nil {
+		return nil, fmt.Errorf("%s: sections: %w", filename, err)
+	}
+	for _, feature := range config.Features {
+		if _, ok := configFeatures[feature]; !ok {
+			names := make([]string, 0, len(configFeatures))
+			for name := range configFeatures {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("%s: unknown feature %q: must be one of %s", filename, feature, strings.Join(names, ", "))
+		}
+	}
+	for _, pattern := range append(config.Include, config.Exclude...) {
+		if err := analyzer.CheckPackagePattern(pattern); err != nil {
+			return nil, fmt.Errorf("%s: invalid package pattern %q: %w", filename, pattern, err)
+		}
+	}
+	for _, check := range config.Checks {
+		if !slices.Contains(checks, check) {
+			return nil, fmt.Errorf("%s: unknown check %q: must be one of %s", filename, check, strings.Join(checks, ", "))
+		}
+	}
+	if _, err := analyzer.ParseFailOn(strings.Join(config.FailOn, ",")); err != nil {
+		return nil, fmt.Errorf("%s: failOn: %w", filename, err)
+	}
+	if config.Path != "" && !filepath.IsAbs(config.Path) {
+		config.Path = filepath.Join(filepath.Dir(filename), config.Path)
+	}
+
+	return &config, nil
+}
+
+// applyFlags sets the flags the config file covers, except those given
+// explicitly on the command line.
+func (c *Config) applyFlags(fs *flag.FlagSet) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	set := func(name, value string) error {
+		if explicit[name] {
+			return nil
+		}
+		return fs.Set(name, value)
+	}
+
+	if c.Path != "" {
+		if err := set("path", c.Path); err != nil {
+			return err
+		}
+	}
+	if c.ExportedOnly != nil {
+		if err := set("exported-only", strconv.FormatBool(*c.ExportedOnly)); err != nil {
+			return err
+		}
+	}
+	if c.Format != "" {
+		if err := set("format", c.Format); err != nil {
+			return err
+		}
+	}
+	for _, feature := range c.Features {
+		if err := set(configFeatures[feature], "true"); err != nil {
+			return err
+		}
+	}
+	for name, list := range map[string][]string{
+		"sections":   c.Sections,
+		"implements": c.Implements,
+		"check":      c.Checks,
+		"fail-on":    c.FailOn,
+	} {
+		if len(list) > 0 {
+			if err := set(name, strings.Join(list, ",")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findConfig returns the ConfigFile in dir or the nearest directory above
+// it, stopping at the module root, or "" if there is none.
+func findConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		filename := filepath.Join(dir, ConfigFile)
+		if _, err := os.Stat(filename); err == nil {
+			return filename, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
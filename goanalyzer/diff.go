@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"goanalyzer/analyzer"
+)
+
+// runDiff implements "goanalyzer diff old.json new.json" and returns the
+// exit status: 0, or 1 when the results differ with -exit-code or hold
+// breaking changes with -fail-on-breaking, and 2 on errors.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goanalyzer diff [flags] old.json new.json")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "text", "Output format: "+strings.Join(analyzer.DiffFormats, " or "))
+	exitCode := fs.Bool("exit-code", false, "Exit with status 1 when the results differ")
+	failOnBreaking := fs.Bool("fail-on-breaking", false, "Exit with status 1 when declarations were removed, signatures changed or implementations broke")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	if !slices.Contains(analyzer.DiffFormats, *format) {
+		fmt.Fprintf(os.Stderr, "Invalid -format %q: must be one of %s\n", *format, strings.Join(analyzer.DiffFormats, ", "))
+		return 2
+	}
+
+	var results [2]*analyzer.Result
+	for i, filename := range fs.Args() {
+		result, err := readResult(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading result: %v\n", err)
+			return 2
+		}
+		results[i] = result
+	}
+
+	diff, err := analyzer.Diff(results[0], results[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error comparing results: %v\n", err)
+		return 2
+	}
+	if err := analyzer.WriteDiff(os.Stdout, diff, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 2
+	}
+	if *exitCode && !diff.Empty() || *failOnBreaking && diff.Breaking() {
+		return 1
+	}
+	return 0
+}
+
+// readResult reads a result written with -format json.
+func readResult(filename string) (*analyzer.Result, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var result analyzer.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"goanalyzer/analyzer"
+)
+
+// runExtractInterface implements "goanalyzer extract-interface -struct
+// name", which writes an interface declaring the exported methods of one
+// analyzed struct, and returns the exit status: 0, or 2 on errors,
+// including a struct that is not found or has no methods to extract.
+func runExtractInterface(args []string) int {
+	fs := flag.NewFlagSet("extract-interface", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goanalyzer extract-interface -struct name [flags]")
+		fs.PrintDefaults()
+	}
+	name := fs.String("struct", "", "Struct to extract the interface of, either plain or qualified by import path or its last elements, such as pg.Store")
+	rootPath := fs.String("path", ".", "Root path to analyze")
+	ifaceName := fs.String("name", "", "Name of the interface (default the name of the struct)")
+	methods := fs.String("methods", "", "Regular expression the names of the methods to declare must match (default all exported methods)")
+	out := fs.String("out", "", "Directory to write <interface>.go to (default standard output)")
+	pkgName := fs.String("package", "", "Package name of the interface (default the base name of -out, or interfaces)")
+	tags := fs.String("tags", "", "Comma-separated build tags to load packages with")
+	fs.Parse(args)
+
+	if *name == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	var filter *regexp.Regexp
+	if *methods != "" {
+		var err error
+		if filter, err = regexp.Compile(*methods); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -methods: %v\n", err)
+			return 2
+		}
+	}
+	if *pkgName == "" {
+		*pkgName = "interfaces"
+		if *out != "" {
+			if abs, err := filepath.Abs(*out); err == nil {
+				*pkgName = filepath.Base(abs)
+			}
+		}
+	}
+	absPath, err := filepath.Abs(*rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+		return 2
+	}
+
+	az := analyzer.New(func(opts *analyzer.Options) {
+		opts.SkipVendor = true
+		opts.Tags = *tags
+		opts.StructuredTypes = true
+		opts.Sections = map[string]bool{"structs": true}
+	})
+	result, err := az.Analyze(context.Background(), absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error analyzing %s: %v\n", absPath, err)
+		return 2
+	}
+	for _, msg := range result.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	}
+
+	strct, err := analyzer.LookupStruct(result, *name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *ifaceName == "" {
+		*ifaceName = strct.Name
+	}
+	src, err := analyzer.GenerateInterface(strct, *ifaceName, *pkgName, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error extracting an interface from %s: %v\n", *name, err)
+		return 2
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return 0
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", *out, err)
+		return 2
+	}
+	file := filepath.Join(*out, strings.ToLower(*ifaceName)+".go")
+	if err := os.WriteFile(file, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", file, err)
+		return 2
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", file)
+	return 0
+}
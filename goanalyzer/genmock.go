@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"goanalyzer/analyzer"
+)
+
+// runGenmock implements "goanalyzer genmock -interface name", which writes
+// a mock of one analyzed interface, and returns the exit status: 0, or 2 on
+// errors, including an interface that is not found or cannot be mocked.
+func runGenmock(args []string) int {
+	fs := flag.NewFlagSet("genmock", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goanalyzer genmock -interface name [flags]")
+		fs.PrintDefaults()
+	}
+	name := fs.String("interface", "", "Interface to mock, either plain or qualified by import path or its last elements, such as store.Store")
+	rootPath := fs.String("path", ".", "Root path to analyze")
+	out := fs.String("out", "", "Directory to write <interface>_mock.go to (default standard output)")
+	pkgName := fs.String("package", "", "Package name of the mock (default the base name of -out, or mocks)")
+	style := fs.String("style", "minimal", "Mock style: "+strings.Join(analyzer.MockStyles, " or "))
+	tags := fs.String("tags", "", "Comma-separated build tags to load packages with")
+	fs.Parse(args)
+
+	if *name == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if !slices.Contains(analyzer.MockStyles, *style) {
+		fmt.Fprintf(os.Stderr, "Invalid -style %q: must be one of %s\n", *style, strings.Join(analyzer.MockStyles, ", "))
+		return 2
+	}
+	if *pkgName == "" {
+		*pkgName = "mocks"
+		if *out != "" {
+			if abs, err := filepath.Abs(*out); err == nil {
+				*pkgName = filepath.Base(abs)
+			}
+		}
+	}
+	absPath, err := filepath.Abs(*rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+		return 2
+	}
+
+	az := analyzer.New(func(opts *analyzer.Options) {
+		opts.SkipVendor = true
+		opts.Tags = *tags
+		opts.StructuredTypes = true
+		opts.Sections = map[string]bool{"interfaces": true}
+	})
+	result, err := az.Analyze(context.Background(), absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error analyzing %s: %v\n", absPath, err)
+		return 2
+	}
+	for _, msg := range result.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	}
+
+	iface, err := analyzer.LookupInterface(result, *name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	src, err := analyzer.GenerateMock(iface, *pkgName, *style)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating mock of %s: %v\n", *name, err)
+		return 2
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return 0
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", *out, err)
+		return 2
+	}
+	file := filepath.Join(*out, strings.ToLower(iface.Name)+"_mock.go")
+	if err := os.WriteFile(file, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", file, err)
+		return 2
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", file)
+	return 0
+}
@@ -2,6 +2,12 @@ module goanalyzer
 
 go 1.21
 
-require golang.org/x/tools v0.17.0
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/fsnotify/fsnotify v1.7.0
+	golang.org/x/mod v0.14.0
+	golang.org/x/tools v0.17.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require golang.org/x/mod v0.14.0 // indirect
+require golang.org/x/sys v0.16.0 // indirect
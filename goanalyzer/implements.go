@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"goanalyzer/analyzer"
+)
+
+// runImplements implements "goanalyzer implements -interface name", which
+// lists the structs implementing one interface instead of a full report,
+// and returns the exit status: 0, or 2 on errors, including an interface
+// that is not found.
+func runImplements(args []string) int {
+	fs := flag.NewFlagSet("implements", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goanalyzer implements -interface name [flags]")
+		fs.PrintDefaults()
+	}
+	name := fs.String("interface", "", "Interface to find the implementers of, either plain or qualified by import path or its last elements, such as store.Store")
+	rootPath := fs.String("path", ".", "Root path to analyze")
+	format := fs.String("format", "text", "Output format: "+strings.Join(analyzer.ImplementersFormats, " or "))
+	tags := fs.String("tags", "", "Comma-separated build tags to load packages with")
+	tests := fs.Bool("tests", false, "Also list the structs declared in _test.go files, such as test doubles")
+	fs.Parse(args)
+
+	if *name == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if !slices.Contains(analyzer.ImplementersFormats, *format) {
+		fmt.Fprintf(os.Stderr, "Invalid -format %q: must be one of %s\n", *format, strings.Join(analyzer.ImplementersFormats, ", "))
+		return 2
+	}
+	absPath, err := filepath.Abs(*rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+		return 2
+	}
+
+	// Interfaces from imported packages and the common standard library
+	// ones are matched too, so that io.Reader can be asked for.
+	az := analyzer.New(func(opts *analyzer.Options) {
+		opts.SkipVendor = true
+		opts.Tags = *tags
+		opts.Tests = *tests
+		opts.ExternalInterfaces = true
+		opts.StdlibInterfaces = analyzer.DefaultStdlibInterfaces
+		opts.Sections = map[string]bool{"interfaces": true, "structs": true, "externalInterfaces": true}
+	})
+	result, err := az.Analyze(context.Background(), absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error analyzing %s: %v\n", absPath, err)
+		return 2
+	}
+	for _, msg := range result.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	}
+
+	impls, err := analyzer.Implementers(result, *name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if err := analyzer.WriteImplementers(os.Stdout, impls, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 2
+	}
+	return 0
+}
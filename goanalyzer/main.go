@@ -1,310 +1,416 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
-	"go/types"
-	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
-	"golang.org/x/tools/go/packages"
+	"goanalyzer/analyzer"
 )
 
-type Position struct {
-	Path string `json:"path"`
-	Line int    `json:"line"`
-}
-
-type Declaration struct {
-	Name     string   `json:"name"`
-	Position Position `json:"position"`
-}
-
-type ParamInfo struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
-}
-
-type MethodInfo struct {
-	Name            string        `json:"name"`
-	Position        Position      `json:"position"`
-	Parameters      []ParamInfo   `json:"parameters"`
-	ReturnTypes     []string      `json:"returnTypes"`
-	ImplementedFrom []Declaration `json:"implementedFrom"`
-}
-
-type InterfaceInfo struct {
-	Name     string       `json:"name"`
-	Position Position     `json:"position"`
-	Methods  []MethodInfo `json:"methods"`
-}
-
-type StructInfo struct {
-	Name                  string        `json:"name"`
-	Position              Position      `json:"position"`
-	Methods               []MethodInfo  `json:"methods"`
-	EmbeddedTypes         []string      `json:"embeddedTypes"`
-	ImplementedInterfaces []Declaration `json:"implementedInterfaces"`
-}
-
-type AnalysisResult struct {
-	Interfaces []InterfaceInfo `json:"interfaces"`
-	Structs    []StructInfo    `json:"structs"`
-}
+// checks are the sections -check accepts. Each is a -fail-on category
+// whose analysis only runs when asked for.
+var checks = []string{"cycles"}
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		case "serve":
+			os.Exit(runServe(os.Args[2:]))
+		case "implements":
+			os.Exit(runImplements(os.Args[2:]))
+		case "genmock":
+			os.Exit(runGenmock(os.Args[2:]))
+		case "extract-interface":
+			os.Exit(runExtractInterface(os.Args[2:]))
+		}
+	}
+
 	rootPath := flag.String("path", ".", "Root path to analyze")
+	configPath := flag.String("config", "", "YAML file with package patterns, report sections and output settings; flags override it (default: "+ConfigFile+" in -path or above it, up to the module root)")
+	var implements stringList
+	flag.Var(&implements, "implements", "Only report structs implementing the named interface (repeatable, comma-separated)")
+	var include, exclude stringList
+	flag.Var(&include, "include", `Only analyze packages whose import path matches one of these patterns (repeatable, comma-separated): globs such as "example.com/app/**" where ** spans path elements, paths ending in "/..." for everything below, or regular expressions after "re:"`)
+	flag.Var(&exclude, "exclude", `Skip packages whose import path matches one of these patterns, as for -include, such as "**/mocks/**" (repeatable, comma-separated)`)
+	implementsMode := flag.String("implements-mode", "any", "How multiple -implements names combine: any or all")
+	methodDeps := flag.Bool("method-deps", false, "Report the packages referenced by each struct's method bodies")
+	examples := flag.Bool("examples", false, "Load test files and report Example functions")
+	tests := flag.Bool("tests", false, "Also report the interfaces and structs declared in _test.go files, marked inTest")
+	skipVendor := flag.Bool("skip-vendor", true, "Skip packages under vendor directories")
+	onlyModule := flag.Bool("only-module", false, "Only analyze packages belonging to the module in go.mod")
+	goroutines := flag.Bool("goroutines", false, "Report goroutine launch sites in each method")
+	panics := flag.Bool("panics", false, "Report panic and re-panic sites in each method")
+	tokenCounts := flag.Bool("token-counts", false, "Report the number of tokens in each method body")
+	returnedErrors := flag.Bool("returned-errors", false, "Report the error types each method returns, as far as known statically")
+	explain := flag.String("explain", "", "Describe the named type in detail instead of writing a report; qualify the name with its import path, or its last elements such as store.Store, if it is ambiguous")
+	typeStyleFlag := flag.String("type-style", "relative", "How types name their package: full (import path), relative (unqualified within the declaring package) or short (package name)")
+	maxDepth := flag.Int("max-depth", -1, "Only analyze packages at most this many directories below -path (0 for -path itself, -1 for no limit)")
+	fieldAccess := flag.Bool("field-access", false, "Report the methods reading and writing each struct field")
+	includeSource := flag.Bool("include-source", false, "Attach the source text of each interface, struct and method")
+	sourceMaxLines := flag.Int("source-max-lines", 20, "Cut -include-source texts to this many lines, keeping at least the signature (0 for no limit)")
+	includeLocals := flag.Bool("include-locals", false, "Also report struct and interface types declared inside functions, in localTypes")
+	packageImports := flag.Bool("package-imports", false, "Report each main module package's imports, split into those of the main module and external ones, in packageImports; -format deps-dot draws them")
+	callGraph := flag.String("callgraph", "", "Report the calls between analyzed functions and methods, in callGraph, found with this algorithm: "+strings.Join(analyzer.CallGraphAlgorithms, " or "))
+	externalIfaces := flag.Bool("external-interfaces", false, "Also report implemented interfaces from imported packages, such as io.Reader")
+	stdlibIfaces := flag.Bool("stdlib-interfaces", false, "Also report implemented standard library interfaces from -stdlib-interface-list, whether imported or not")
+	stdlibIfaceList := flag.String("stdlib-interface-list", strings.Join(analyzer.DefaultStdlibInterfaces, ","), "Comma-separated standard library interfaces checked with -stdlib-interfaces, such as io.Reader or error")
+	copyThreshold := flag.Int64("copy-threshold", 64, "Size in bytes above which structs passed by value to interface parameters are reported in valueCopyWarnings")
+	basicConversions := flag.Bool("basic-conversions", false, "Also report conversions between named and basic types")
+	exportedOnly := flag.Bool("exported-only", false, "Only report exported interfaces and structs")
+	stats := flag.Bool("stats", false, "Include summary statistics")
+	top := flag.Int("top", 10, "Number of entries in the -stats rankings (0 for all)")
+	namingLint := flag.Bool("naming-lint", false, "Suggest idiomatic -er names for interfaces")
+	maxParams := flag.Int("max-params", 0, "Report methods with more than this many parameters (0 disables)")
+	format := flag.String("format", "json", "Output format: json, api, toml, cypher, text, index, fingerprint, lsp-symbols, deps-dot, dot, mermaid, plantuml or sarif")
+	maxMethods := flag.Int("max-methods", 0, "List at most this many methods per class with -format mermaid or plantuml (0 for all)")
+	groupPackages := flag.Bool("group-packages", false, "Group classes by package with -format plantuml")
+	tags := flag.String("tags", "", "Comma-separated build tags to apply when loading packages")
+	goos := flag.String("goos", "", "GOOS to load packages for (default: the host's)")
+	goarch := flag.String("goarch", "", "GOARCH to load packages for (default: the host's)")
+	includeGenerated := flag.Bool("include-generated", false, "Include declarations from generated files")
+	overlayPath := flag.String("overlay", "", "JSON file of unsaved file contents to analyze instead of the files on disk")
+	stdlibTypes := flag.String("stdlib-types", strings.Join(analyzer.DefaultStdlibTypes, ","), "Comma-separated standard library types checked for satisfying analyzed interfaces (empty disables)")
+	splitOutput := flag.String("split-output", "", "Write one file per package into this directory instead of stdout")
+	structuredTypes := flag.Bool("structured-types", false, "Describe parameter and return types structurally as well as by name")
+	countOnly := flag.Bool("count-only", false, "Only count interfaces and structs, skipping method sets and implements checks")
+	stdinSrc := flag.Bool("stdin-src", false, "Analyze Go source read from stdin instead of -path")
+	at := flag.String("at", "", "Analyze -path as of this git revision, checked out into a temporary worktree")
+	sectionList := flag.String("sections", strings.Join(analyzer.DefaultSections, ","), "Comma-separated result sections to report; stats, examples and externalInterfaces also enable their analyses")
+	minDocCoverage := flag.Float64("min-doc-coverage", 0, "Exit with status 1 when less than this percentage of exported interfaces, structs and methods have doc comments")
+	failOnList := flag.String("fail-on", "", "Comma-separated categories that make the exit status 1 when they have entries: "+strings.Join(analyzer.FailOnCategories(), ", "))
+	checkList := flag.String("check", "", "Comma-separated checks to report, exiting with status 1 when they find anything: "+strings.Join(checks, ", "))
+	useCache := flag.Bool("cache", false, "Reuse the results of earlier runs for unchanged packages, kept under the user's cache directory in goanalyzer")
+	cacheDir := flag.String("cache-dir", "", "Directory for -cache (default: goanalyzer under the user's cache directory)")
+	watch := flag.Bool("watch", false, "Keep running and write a new result whenever Go files below -path change; implies -cache")
+	workers := flag.Int("workers", 0, "Number of packages analyzed at once (0 for GOMAXPROCS)")
+	timeout := flag.Duration("timeout", 0, "Abort analysis after this long and report partial results (0 disables)")
 	flag.Parse()
 
-	absPath, err := filepath.Abs(*rootPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
-		os.Exit(1)
+	if *configPath == "" {
+		found, err := findConfig(*rootPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating config: %v\n", err)
+			os.Exit(1)
+		}
+		*configPath = found
 	}
-
-	result := analyze(absPath)
-	jsonResult, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
-		os.Exit(1)
+	var config Config
+	if *configPath != "" {
+		loaded, err := loadConfig(*configPath)
+		if err == nil {
+			err = loaded.applyFlags(flag.CommandLine)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		config = *loaded
 	}
-	fmt.Println(string(jsonResult))
-}
 
-func analyze(rootPath string) AnalysisResult {
-	var result AnalysisResult
-	result.Interfaces = make([]InterfaceInfo, 0)
-	result.Structs = make([]StructInfo, 0)
+	for _, pattern := range append(include, exclude...) {
+		if err := analyzer.CheckPackagePattern(pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid package pattern %q: %v\n", pattern, err)
+			os.Exit(1)
+		}
+	}
+	if len(include) == 0 {
+		include = config.Include
+	}
+	if len(exclude) == 0 {
+		exclude = config.Exclude
+	}
 
-	// Configure package loading
-	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
-			packages.NeedImports | packages.NeedTypes | packages.NeedTypesInfo |
-			packages.NeedSyntax,
-		Dir: rootPath,
+	if !analyzer.IsValidFormat(*format) {
+		fmt.Fprintf(os.Stderr, "Invalid -format %q: must be one of %s\n", *format, strings.Join(analyzer.Formats, ", "))
+		os.Exit(1)
 	}
 
-	pkgs, err := packages.Load(cfg, "./...")
+	selected, err := analyzer.ParseSections(*sectionList)
 	if err != nil {
-		log.Printf("Error loading packages: %v", err)
-		return result
+		fmt.Fprintf(os.Stderr, "Invalid -sections: %v\n", err)
+		os.Exit(1)
 	}
-
-	// Process each package
-	for _, pkg := range pkgs {
-		if len(pkg.Errors) > 0 {
-			for _, err := range pkg.Errors {
-				log.Printf("Error in package %s: %v", pkg.PkgPath, err)
-			}
+	failOn, err := analyzer.ParseFailOn(*failOnList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -fail-on: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range strings.Split(*checkList, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
 			continue
 		}
-
-		scope := pkg.Types.Scope()
-		for _, name := range scope.Names() {
-			obj := scope.Lookup(name)
-			if obj == nil {
-				continue
-			}
-
-			switch t := obj.Type().Underlying().(type) {
-			case *types.Interface:
-				if t.NumMethods() > 0 {
-					iface := processInterface(obj, pkg)
-					if iface != nil {
-						result.Interfaces = append(result.Interfaces, *iface)
-					}
-				}
-			case *types.Struct:
-				strct := processStruct(obj, pkg, result.Interfaces)
-				if strct != nil {
-					result.Structs = append(result.Structs, *strct)
-				}
-			}
+		if !slices.Contains(checks, name) {
+			fmt.Fprintf(os.Stderr, "Invalid -check %q: must be one of %s\n", name, strings.Join(checks, ", "))
+			os.Exit(1)
+		}
+		if !slices.Contains(failOn, name) {
+			failOn = append(failOn, name)
+		}
+	}
+	for _, name := range failOn {
+		if name == "tooManyParams" && *maxParams == 0 {
+			fmt.Fprintln(os.Stderr, "Invalid -fail-on: tooManyParams requires -max-params")
+			os.Exit(1)
+		}
+		if name != analyzer.FailOnErrors {
+			selected[name] = true
 		}
 	}
 
-	return result
-}
+	if *minDocCoverage > 0 {
+		selected["undocumented"] = true
+	}
 
-func processInterface(obj types.Object, pkg *packages.Package) *InterfaceInfo {
-	iface, ok := obj.Type().Underlying().(*types.Interface)
-	if !ok {
-		return nil
+	// Selecting a section enables the analysis behind it, and the flags
+	// enabling an analysis select its section.
+	*stats = *stats || selected["stats"]
+	*examples = *examples || selected["examples"]
+	*externalIfaces = *externalIfaces || selected["externalInterfaces"]
+	*namingLint = *namingLint || selected["namingSuggestions"]
+	*includeLocals = *includeLocals || selected["localTypes"]
+	*packageImports = *packageImports || selected["packageImports"] || *format == "deps-dot"
+	if *callGraph == "" && selected["callGraph"] {
+		*callGraph = "cha"
+	}
+	for name, enabled := range map[string]bool{
+		"stats":              *stats,
+		"examples":           *examples,
+		"externalInterfaces": *externalIfaces || *stdlibIfaces,
+		"tooManyParams":      *maxParams > 0,
+		"namingSuggestions":  *namingLint,
+		"localTypes":         *includeLocals,
+		"callGraph":          *callGraph != "",
+		"api":                *format == "api",
+		"lspSymbols":         *format == "lsp-symbols",
+		"packageImports":     *packageImports,
+	} {
+		selected[name] = selected[name] || enabled
 	}
 
-	pos := pkg.Fset.Position(obj.Pos())
-	info := &InterfaceInfo{
-		Name: obj.Name(),
-		Position: Position{
-			Path: makeRelativePath(pos.Filename),
-			Line: pos.Line,
-		},
-		Methods: make([]MethodInfo, 0),
+	if *countOnly && (*format != "json" || *splitOutput != "") {
+		fmt.Fprintln(os.Stderr, "-count-only writes JSON to stdout and cannot be combined with -format or -split-output")
+		os.Exit(1)
 	}
 
-	for i := 0; i < iface.NumMethods(); i++ {
-		method := iface.Method(i)
-		methodPos := pkg.Fset.Position(method.Pos())
-		signature := method.Type().(*types.Signature)
+	if *explain != "" && (*format != "json" || *splitOutput != "" || *countOnly) {
+		fmt.Fprintln(os.Stderr, "-explain writes text to stdout and cannot be combined with -format, -split-output or -count-only")
+		os.Exit(1)
+	}
 
-		methodInfo := MethodInfo{
-			Name: method.Name(),
-			Position: Position{
-				Path: makeRelativePath(methodPos.Filename),
-				Line: methodPos.Line,
-			},
-			Parameters:      extractParams(signature),
-			ReturnTypes:     extractReturnTypes(signature),
-			ImplementedFrom: make([]Declaration, 0),
-		}
-		info.Methods = append(info.Methods, methodInfo)
+	if !slices.Contains(analyzer.TypeStyles, *typeStyleFlag) {
+		fmt.Fprintf(os.Stderr, "Invalid -type-style %q: must be one of %s\n", *typeStyleFlag, strings.Join(analyzer.TypeStyles, ", "))
+		os.Exit(1)
 	}
 
-	return info
-}
+	if *callGraph != "" && !slices.Contains(analyzer.CallGraphAlgorithms, *callGraph) {
+		fmt.Fprintf(os.Stderr, "Invalid -callgraph %q: must be one of %s\n", *callGraph, strings.Join(analyzer.CallGraphAlgorithms, ", "))
+		os.Exit(1)
+	}
 
-func processStruct(obj types.Object, pkg *packages.Package, allInterfaces []InterfaceInfo) *StructInfo {
-	named, ok := obj.Type().(*types.Named)
-	if !ok {
-		return nil
+	if *implementsMode != "any" && *implementsMode != "all" {
+		fmt.Fprintf(os.Stderr, "Invalid -implements-mode %q: must be any or all\n", *implementsMode)
+		os.Exit(1)
 	}
 
-	strct, ok := named.Underlying().(*types.Struct)
-	if !ok {
-		return nil
+	absPath, err := filepath.Abs(*rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+		os.Exit(1)
 	}
 
-	pos := pkg.Fset.Position(obj.Pos())
-	info := &StructInfo{
-		Name: obj.Name(),
-		Position: Position{
-			Path: makeRelativePath(pos.Filename),
-			Line: pos.Line,
-		},
-		Methods:               make([]MethodInfo, 0),
-		EmbeddedTypes:         make([]string, 0),
-		ImplementedInterfaces: make([]Declaration, 0),
+	var stdlibTypeList stringList
+	stdlibTypeList.Set(*stdlibTypes)
+	var stdlibIfaceNames stringList
+	if *stdlibIfaces {
+		stdlibIfaceNames.Set(*stdlibIfaceList)
 	}
 
-	// Get embedded types
-	for i := 0; i < strct.NumFields(); i++ {
-		field := strct.Field(i)
-		if field.Anonymous() {
-			info.EmbeddedTypes = append(info.EmbeddedTypes, types.TypeString(field.Type(), nil))
+	var overlay map[string][]byte
+	if *overlayPath != "" {
+		overlay, err = loadOverlay(*overlayPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading overlay: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
-	// Get methods from both value and pointer receivers
-	methodSet := types.NewMethodSet(named)
-	ptrMethodSet := types.NewMethodSet(types.NewPointer(named))
-
-	// Helper function to process method sets
-	processMethodSet := func(ms *types.MethodSet) {
-		for i := 0; i < ms.Len(); i++ {
-			sel := ms.At(i)
-			method := sel.Obj().(*types.Func)
-			methodPos := pkg.Fset.Position(method.Pos())
-			signature := method.Type().(*types.Signature)
+	if *stdinSrc && *at != "" {
+		fmt.Fprintln(os.Stderr, "-stdin-src and -at cannot be combined")
+		os.Exit(1)
+	}
 
-			// Skip if method already exists
-			methodExists := false
-			for _, existingMethod := range info.Methods {
-				if existingMethod.Name == method.Name() {
-					methodExists = true
-					break
-				}
-			}
-			if methodExists {
-				continue
-			}
+	if *watch && (*stdinSrc || *at != "") {
+		fmt.Fprintln(os.Stderr, "-watch cannot be combined with -stdin-src or -at")
+		os.Exit(1)
+	}
 
-			methodInfo := MethodInfo{
-				Name: method.Name(),
-				Position: Position{
-					Path: makeRelativePath(methodPos.Filename),
-					Line: methodPos.Line,
-				},
-				Parameters:      extractParams(signature),
-				ReturnTypes:     extractReturnTypes(signature),
-				ImplementedFrom: make([]Declaration, 0),
+	var worktree *gitWorktree
+	if *at != "" {
+		worktree, err = addWorktree(absPath, *at)
+		if err == nil {
+			absPath, err = worktree.path(absPath)
+			if err != nil {
+				worktree.remove()
 			}
-			info.Methods = append(info.Methods, methodInfo)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking out -at revision: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
-	// Process both value and pointer receiver methods
-	processMethodSet(methodSet)
-	processMethodSet(ptrMethodSet)
-
-	// Check interface implementations
-	ptrType := types.NewPointer(named)
-	for _, iface := range allInterfaces {
-		ifaceObj := pkg.Types.Scope().Lookup(iface.Name)
-		if ifaceObj == nil {
-			continue
+	if *stdinSrc {
+		dir, err := writeStdinPackage(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading source from stdin: %v\n", err)
+			os.Exit(1)
 		}
+		absPath = dir
+	}
 
-		ifaceType, ok := ifaceObj.Type().Underlying().(*types.Interface)
-		if !ok {
-			continue
+	// Temporary directories never repeat, so their results are not cached.
+	var resultCacheDir string
+	if (*useCache || *cacheDir != "" || *watch) && !*stdinSrc && worktree == nil {
+		resultCacheDir = *cacheDir
+		if resultCacheDir == "" {
+			resultCacheDir, err = analyzer.DefaultCacheDir()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error locating cache directory: %v\n", err)
+				os.Exit(1)
+			}
 		}
+	}
 
-		// Check both pointer and value receivers
-		if types.Implements(named, ifaceType) || types.Implements(ptrType, ifaceType) {
-			info.ImplementedInterfaces = append(info.ImplementedInterfaces, Declaration{
-				Name:     iface.Name,
-				Position: iface.Position,
-			})
-
-			// Update method implementation info
-			for i := range info.Methods {
-				method := &info.Methods[i]
-				for _, ifaceMethod := range iface.Methods {
-					if method.Name == ifaceMethod.Name {
-						method.ImplementedFrom = append(method.ImplementedFrom, Declaration{
-							Name:     iface.Name + "." + ifaceMethod.Name,
-							Position: ifaceMethod.Position,
-						})
-					}
-				}
-			}
+	az := analyzer.New(analyzer.WithOptions(analyzer.Options{
+		MethodDependencies: *methodDeps,
+		PublicAPI:          *format == "api",
+		LSPSymbols:         *format == "lsp-symbols",
+		PackageImports:     *packageImports,
+		Examples:           *examples,
+		Tests:              *tests,
+		SkipVendor:         *skipVendor,
+		OnlyModule:         *onlyModule,
+		Goroutines:         *goroutines,
+		Panics:             *panics,
+		TokenCounts:        *tokenCounts,
+		ReturnedErrors:     *returnedErrors,
+		IncludeLocals:      *includeLocals,
+		CallGraph:          *callGraph,
+		IncludeSource:      *includeSource,
+		SourceMaxLines:     *sourceMaxLines,
+		FieldAccess:        *fieldAccess,
+		MaxDepth:           *maxDepth,
+		Explain:            *explain,
+		Tags:               *tags,
+		GOOS:               *goos,
+		GOARCH:             *goarch,
+		Overlay:            overlay,
+		IncludeGenerated:   *includeGenerated,
+		StdlibTypes:        stdlibTypeList,
+		StructuredTypes:    *structuredTypes,
+		BasicConversions:   *basicConversions,
+		CopyThreshold:      *copyThreshold,
+		ExternalInterfaces: *externalIfaces,
+		StdlibInterfaces:   stdlibIfaceNames,
+		Sections:           selected,
+		Include:            include,
+		Exclude:            exclude,
+		CountOnly:          *countOnly,
+		Workers:            *workers,
+		CacheDir:           resultCacheDir,
+		ExportedOnly:       *exportedOnly,
+		Implements:         implements,
+		ImplementsAll:      *implementsMode == "all",
+		MaxParams:          *maxParams,
+		NamingLint:         *namingLint,
+		Stats:              *stats,
+		Top:                *top,
+		TypeStyle:          *typeStyleFlag,
+		MaxMethods:         *maxMethods,
+		GroupPackages:      *groupPackages,
+		FailOn:             failOn,
+		MinDocCoverage:     *minDocCoverage,
+	}))
+	run := func() *analyzer.Result {
+		ctx := context.Background()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
 		}
+		// Load failures are reported in the result's errors like any other.
+		result, _ := az.Analyze(ctx, absPath)
+		return result
 	}
+	// report writes result and returns why it fails -fail-on and
+	// -min-doc-coverage, which only apply to full reports.
+	report := func(result *analyzer.Result) ([]string, error) {
+		if *explain != "" {
+			if err := analyzer.WriteExplanation(os.Stdout, *result, *explain); err != nil {
+				return nil, fmt.Errorf("explaining type: %w", err)
+			}
+			return nil, nil
+		}
+		if *countOnly {
+			if err := analyzer.WriteCounts(os.Stdout, result); err != nil {
+				return nil, fmt.Errorf("writing output: %w", err)
+			}
+			return nil, nil
+		}
 
-	return info
-}
+		var err error
+		if *splitOutput != "" {
+			err = az.WriteSplit(*splitOutput, result, *format)
+		} else {
+			err = az.Write(os.Stdout, result, *format)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("writing output: %w", err)
+		}
+		return az.Failures(result), nil
+	}
 
-func extractParams(signature *types.Signature) []ParamInfo {
-	params := make([]ParamInfo, 0)
-	for i := 0; i < signature.Params().Len(); i++ {
-		param := signature.Params().At(i)
-		params = append(params, ParamInfo{
-			Name: param.Name(),
-			Type: types.TypeString(param.Type(), nil),
+	// Watching reports failures without exiting, as the next change may
+	// fix them.
+	if *watch {
+		err := watchTree(absPath, func() {
+			failed, err := report(run())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error %v\n", err)
+			} else if len(failed) > 0 {
+				fmt.Fprintf(os.Stderr, "Failing: %s\n", strings.Join(failed, "; "))
+			}
 		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error watching %s: %v\n", absPath, err)
+			os.Exit(1)
+		}
+		return
 	}
-	return params
-}
 
-func extractReturnTypes(signature *types.Signature) []string {
-	results := make([]string, 0)
-	for i := 0; i < signature.Results().Len(); i++ {
-		result := signature.Results().At(i)
-		results = append(results, types.TypeString(result.Type(), nil))
+	result := run()
+	if *stdinSrc {
+		os.RemoveAll(absPath)
+	}
+	if worktree != nil {
+		worktree.remove()
+		result.Revision = &worktree.revision
+	}
+	failed, err := report(result)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		os.Exit(1)
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(os.Stderr, "Failing: %s\n", strings.Join(failed, "; "))
+		os.Exit(1)
 	}
-	return results
-}
-
-func makeRelativePath(path string) string {
-	// Convert Windows paths to forward slashes
-	path = filepath.ToSlash(path)
-	// Get the last two components of the path (e.g., "internal/repositories/file.go")
-	parts := strings.Split(path, "/")
-	if len(parts) > 2 {
-		return strings.Join(parts[len(parts)-3:], "/")
-	}
-	return path
 }
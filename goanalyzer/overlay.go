@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// loadOverlay reads an overlay file for packages.Config.Overlay. Two JSON
+// shapes are accepted:
+//
+//   - the go command's -overlay format, {"Replace": {"a.go": "/tmp/a.go"}},
+//     mapping each file to another file holding its contents. The empty
+//     replacement, which deletes a file, is refused, as an overlay of
+//     contents cannot express it;
+//   - a plain object mapping each file directly to its contents, e.g.
+//     {"a.go": "package a\n..."}, which lets editors pass unsaved buffers
+//     without writing them to disk.
+//
+// Relative file names are resolved against the working directory, as the go
+// command does.
+func loadOverlay(overlayPath string) (map[string][]byte, error) {
+	data, err := os.ReadFile(overlayPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading overlay: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing overlay: %w", err)
+	}
+
+	overlay := make(map[string][]byte)
+	if replace, ok := raw["Replace"]; ok && len(raw) == 1 {
+		var files map[string]string
+		if err := json.Unmarshal(replace, &files); err != nil {
+			return nil, fmt.Errorf("parsing overlay Replace: %w", err)
+		}
+		for filename, replacement := range files {
+			if replacement == "" {
+				return nil, fmt.Errorf("overlay deletes %s, which is not supported", filename)
+			}
+			content, err := os.ReadFile(replacement)
+			if err != nil {
+				return nil, fmt.Errorf("reading overlay replacement for %s: %w", filename, err)
+			}
+			if err := addOverlayFile(overlay, filename, content); err != nil {
+				return nil, err
+			}
+		}
+		return overlay, nil
+	}
+
+	for filename, value := range raw {
+		var content string
+		if err := json.Unmarshal(value, &content); err != nil {
+			return nil, fmt.Errorf("parsing overlay contents for %s: %w", filename, err)
+		}
+		if err := addOverlayFile(overlay, filename, []byte(content)); err != nil {
+			return nil, err
+		}
+	}
+	return overlay, nil
+}
+
+func addOverlayFile(overlay map[string][]byte, filename string, content []byte) error {
+	absName, err := filepath.Abs(filename)
+	if err != nil {
+		return fmt.Errorf("resolving overlay file %s: %w", filename, err)
+	}
+	overlay[absName] = content
+	return nil
+}
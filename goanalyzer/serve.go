@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goanalyzer/analyzer"
+)
+
+// runServe implements "goanalyzer serve", which answers queries over HTTP
+// until it fails, and returns the exit status:
+//
+//	GET /analyze?path=dir                      the full result for dir
+//	GET /interfaces/{name}/implementers?path=  the structs implementing name
+//	GET /structs/{name}?path=                  the struct name
+//
+// Names are either plain, such as Store, or qualified by import path or its
+// last elements, such as example.com/app/store.Store or store.Store. A name
+// matching declarations of several packages is answered with 409 Conflict
+// and the qualified names to choose from. The path defaults to -path, and relative
+// paths are resolved against it; paths outside it are refused, so that
+// callers cannot run go list on arbitrary directories. Each query analyzes
+// its path anew, reusing the results cached for unchanged packages.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goanalyzer serve [flags]")
+		fs.PrintDefaults()
+	}
+	addr := fs.String("addr", "localhost:8080", "Address to listen on; give a host such as :8080 to accept connections from other machines")
+	rootPath := fs.String("path", ".", "Root path analyzed when a query gives none; queries cannot name paths outside it")
+	sectionList := fs.String("sections", strings.Join(analyzer.DefaultSections, ","), "Comma-separated result sections to report")
+	useCache := fs.Bool("cache", true, "Reuse the results of earlier queries for unchanged packages")
+	cacheDir := fs.String("cache-dir", "", "Directory for -cache (default: goanalyzer under the user's cache directory)")
+	workers := fs.Int("workers", 0, "Number of packages analyzed at once (0 for GOMAXPROCS)")
+	timeout := fs.Duration("timeout", 0, "Abort each analysis after this long and report partial results (0 disables)")
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	selected, err := analyzer.ParseSections(*sectionList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -sections: %v\n", err)
+		return 2
+	}
+	root, err := filepath.Abs(*rootPath)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+		return 2
+	}
+	resultCacheDir := *cacheDir
+	if *useCache && resultCacheDir == "" {
+		resultCacheDir, err = analyzer.DefaultCacheDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating cache directory: %v\n", err)
+			return 2
+		}
+	}
+
+	s := &server{
+		az: analyzer.New(func(opts *analyzer.Options) {
+			opts.SkipVendor = true
+			opts.Sections = selected
+			opts.Workers = *workers
+			opts.CacheDir = resultCacheDir
+		}),
+		root:    root,
+		timeout: *timeout,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyze", s.handleAnalyze)
+	mux.HandleFunc("/interfaces/", s.handleImplementers)
+	mux.HandleFunc("/structs/", s.handleStruct)
+
+	log.Printf("Serving analyses of %s on %s", root, *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+type server struct {
+	az      *analyzer.Analyzer
+	root    string
+	timeout time.Duration
+
+	// mu serializes analyses, as they load whole package graphs.
+	mu sync.Mutex
+}
+
+// errNotFound answers a query with 404, and errForbidden with 403.
+var (
+	errNotFound  = errors.New("not found")
+	errForbidden = errors.New("forbidden")
+)
+
+// analyze analyzes the path a query names, or the server's root.
+func (s *server) analyze(r *http.Request) (*analyzer.Result, error) {
+	path, err := s.resolve(r.URL.Query().Get("path"))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := r.Context()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Load failures are reported in the result's errors like any other.
+	result, _ := s.az.Analyze(ctx, path)
+	return result, nil
+}
+
+// resolve returns the directory path names, relative to the server's root
+// unless absolute, with symbolic links evaluated so that they cannot lead
+// out of the root.
+func (s *server) resolve(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("no directory %s: %w", path, errNotFound)
+	}
+	if rel, err := filepath.Rel(s.root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside %s: %w", path, s.root, errForbidden)
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("no directory %s: %w", path, errNotFound)
+	}
+	return resolved, nil
+}
+
+func (s *server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	result, err := s.analyze(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSONResponse(w, result)
+}
+
+func (s *server) handleImplementers(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/interfaces/"), "/implementers")
+	if !ok || name == "" {
+		http.NotFound(w, r)
+		return
+	}
+	result, err := s.analyze(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Interfaces from outside the result, such as fmt.Stringer, are known
+	// from the structs implementing them.
+	matches := make(map[string]bool)
+	match := func(pkg, declName string) {
+		if analyzer.MatchesName(pkg, declName, name) {
+			matches[pkg+"."+declName] = true
+		}
+	}
+	for _, iface := range result.Interfaces {
+		match(iface.Package, iface.Name)
+	}
+	for _, iface := range result.ExternalInterfaces {
+		match(iface.Package, iface.Name)
+	}
+	for _, strct := range result.Structs {
+		for _, iface := range strct.ImplementedInterfaces {
+			match(iface.Package, iface.Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		writeError(w, fmt.Errorf("no interface %s: %w", name, errNotFound))
+		return
+	case 1:
+	default:
+		qualified := make([]string, 0, len(matches))
+		for decl := range matches {
+			qualified = append(qualified, decl)
+		}
+		writeAmbiguous(w, "interface", name, qualified)
+		return
+	}
+
+	implementers := make([]analyzer.StructInfo, 0)
+	for _, strct := range result.Structs {
+		for _, iface := range strct.ImplementedInterfaces {
+			if matches[iface.Package+"."+iface.Name] {
+				implementers = append(implementers, strct)
+				break
+			}
+		}
+	}
+	writeJSONResponse(w, implementers)
+}
+
+func (s *server) handleStruct(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/structs/")
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+	result, err := s.analyze(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var matches []analyzer.StructInfo
+	for _, strct := range result.Structs {
+		if analyzer.MatchesName(strct.Package, strct.Name, name) {
+			matches = append(matches, strct)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		writeError(w, fmt.Errorf("no struct %s: %w", name, errNotFound))
+	case 1:
+		writeJSONResponse(w, matches[0])
+	default:
+		qualified := make([]string, len(matches))
+		for i, strct := range matches {
+			qualified[i] = strct.Package + "." + strct.Name
+		}
+		writeAmbiguous(w, "struct", name, qualified)
+	}
+}
+
+// writeAmbiguous responds 409 Conflict to a request naming a declaration of
+// the given kind that has several qualified names.
+func writeAmbiguous(w http.ResponseWriter, kind, name string, qualified []string) {
+	sort.Strings(qualified)
+	writeJSONStatus(w, http.StatusConflict, map[string]string{
+		"error": fmt.Sprintf("%s %s is ambiguous: qualify it as one of %s", kind, name, strings.Join(qualified, ", ")),
+	})
+}
+
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+func writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	writeJSONStatus(w, http.StatusOK, v)
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// writeError answers with err as {"error": "..."}, and status 404 for
+// errNotFound, 403 for errForbidden or 500 otherwise.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errForbidden):
+		status = http.StatusForbidden
+	}
+	writeJSONStatus(w, status, map[string]string{"error": err.Error()})
+}
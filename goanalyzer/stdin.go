@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeStdinPackage saves Go source read from r as the only file of a
+// throwaway module and returns the module's directory, which the caller
+// removes when done. Positions are relative to the module root, so they
+// read "stdin.go" rather than containing a random name.
+func writeStdinPackage(r io.Reader) (string, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("reading source: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "goanalyzer-stdin-")
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module stdin\n\ngo 1.21\n"), 0o644)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, "stdin.go"), src, 0o644)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay is how long watchTree waits for further changes before
+// re-analyzing, so that saving several files runs one analysis.
+const watchDelay = 200 * time.Millisecond
+
+// watchTree calls run once, then again whenever Go files, go.mod or go.sum
+// in or below root change. It only returns when watching fails.
+func watchTree(root string, run func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watchDirs(watcher, root, root); err != nil {
+		return err
+	}
+
+	run()
+
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// New directories are watched as well, and may have been
+			// created with Go files already in them.
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchDirs(watcher, root, event.Name); err != nil {
+						return err
+					}
+					timer.Reset(watchDelay)
+					continue
+				}
+			}
+			if event.Has(fsnotify.Chmod) || !affectsAnalysis(event.Name) {
+				continue
+			}
+			timer.Reset(watchDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-timer.C:
+			run()
+		}
+	}
+}
+
+// watchDirs watches dir, in or below root, and the directories below it
+// that the go command would consider, skipping those it ignores. Only root
+// itself is watched whatever its name. Directories removed while they are
+// walked are left out.
+func watchDirs(watcher *fsnotify.Watcher, root, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata") {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+}
+
+func affectsAnalysis(filename string) bool {
+	name := filepath.Base(filename)
+	return strings.HasSuffix(name, ".go") || name == "go.mod" || name == "go.sum"
+}
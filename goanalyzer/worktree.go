@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"goanalyzer/analyzer"
+)
+
+// gitWorktree is a temporary checkout of one commit of the repository
+// containing the analyzed directory.
+type gitWorktree struct {
+	repo     string
+	tmp      string
+	dir      string
+	revision analyzer.Revision
+}
+
+// git runs git in dir and returns its trimmed standard output. Failures
+// carry git's own message.
+func git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// addWorktree checks out ref of the repository containing path into a
+// temporary worktree. The checkout is detached, so refs already checked out
+// elsewhere and a detached HEAD in the main tree both work. Uncommitted
+// changes are not part of the commit; they are left alone, with a warning.
+func addWorktree(path, ref string) (*gitWorktree, error) {
+	repo, err := git(path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", path)
+	}
+	commit, err := git(repo, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %q", ref)
+	}
+	if status, err := git(repo, "status", "--porcelain", "--untracked-files=no"); err == nil && status != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s has uncommitted changes, which are not analyzed with -at\n", repo)
+	}
+
+	tmp, err := os.MkdirTemp("", "goanalyzer-at-")
+	if err != nil {
+		return nil, err
+	}
+	// Name the checkout after the repository so that positions read as
+	// they would in the original tree.
+	dir := filepath.Join(tmp, filepath.Base(repo))
+	if _, err := git(repo, "worktree", "add", "--detach", dir, commit); err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	return &gitWorktree{
+		repo:     repo,
+		tmp:      tmp,
+		dir:      dir,
+		revision: analyzer.Revision{Ref: ref, Commit: commit},
+	}, nil
+}
+
+// path maps a path inside the original repository to the same path in the
+// worktree.
+func (w *gitWorktree) path(path string) (string, error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	rel, err := filepath.Rel(w.repo, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside %s", path, w.repo)
+	}
+	return filepath.Join(w.dir, rel), nil
+}
+
+// remove deletes the worktree and its registration in the repository.
+func (w *gitWorktree) remove() {
+	if _, err := git(w.repo, "worktree", "remove", "--force", w.dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: removing worktree: %v\n", err)
+	}
+	os.RemoveAll(w.tmp)
+	git(w.repo, "worktree", "prune")
+}
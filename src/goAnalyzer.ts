@@ -128,6 +128,21 @@ export class GoAnalyzerService {
         throw new Error('Go installation not found. Please install Go and make sure it\'s in your PATH.');
     }
 
+    // Copies the Go sources and go.mod from sourceDir into destDir, including
+    // the packages in subdirectories such as analyzer.
+    private async copyGoSources(sourceDir: string, destDir: string): Promise<void> {
+        await fs.promises.mkdir(destDir, { recursive: true });
+        for (const entry of await fs.promises.readdir(sourceDir, { withFileTypes: true })) {
+            const sourcePath = path.join(sourceDir, entry.name);
+            const destPath = path.join(destDir, entry.name);
+            if (entry.isDirectory()) {
+                await this.copyGoSources(sourcePath, destPath);
+            } else if (entry.name.endsWith('.go') || entry.name === 'go.mod') {
+                await fs.promises.copyFile(sourcePath, destPath);
+            }
+        }
+    }
+
     private async setupGoModule(goPath: string): Promise<void> {
         // Initialize Go module if it doesn't exist
         if (!fs.existsSync(path.join(this.analyzerPath, 'go.mod'))) {
@@ -200,19 +215,15 @@ export class GoAnalyzerService {
                 const sourceDir = path.join(path.dirname(this.analyzerPath), '..', 'goanalyzer');
                 if (fs.existsSync(sourceDir)) {
                     this.log('Copying analyzer files from:', sourceDir);
-                    for (const file of await fs.promises.readdir(sourceDir)) {
-                        if (file.endsWith('.go') || file === 'go.mod') {
-                            const sourcePath = path.join(sourceDir, file);
-                            const destPath = path.join(this.analyzerPath, file);
-                            await fs.promises.copyFile(sourcePath, destPath);
-                        }
-                    }
+                    await this.copyGoSources(sourceDir, this.analyzerPath);
                 }
 
                 // Setup Go module
                 await this.setupGoModule(goPath);
 
-                const process = cp.spawn(goPath, ['run', '.', '-path', normalizedPath], {
+                // methodsMatch compares types as strings, which only name
+                // the same type alike when qualified by import path.
+                const process = cp.spawn(goPath, ['run', '.', '-path', normalizedPath, '-type-style', 'full'], {
                     cwd: this.analyzerPath
                 });
 